@@ -1,5 +1,10 @@
 package logx
 
+import (
+	"context"
+	"sync/atomic"
+)
+
 // Appender accepts log entries
 type Appender interface {
 
@@ -9,3 +14,77 @@ type Appender interface {
 	// Clone returns new appender with given prefix and tags.
 	Clone(prefix string, tags []string) Appender
 }
+
+// EntryAppender accepts structured log entries with typed Level. Log
+// passes entries to AppendEntry instead of Append if appender implements
+// EntryAppender. Appenders which implement only Append receive level name
+// and fields rendered into line as "key=value" pairs. Level name may be
+// converted back with ParseLevel.
+type EntryAppender interface {
+	Appender
+
+	// AppendEntry sends log entry to appender. AppendEntry should be
+	// thread-safe and should not retain entry after return.
+	AppendEntry(entry *Entry)
+}
+
+// Flusher is implemented by appenders which buffer entries.
+type Flusher interface {
+
+	// Flush writes all buffered entries. Flush should return when all
+	// entries are written or context is done.
+	Flush(ctx context.Context) error
+}
+
+// ErrorHandler is called with errors of appender writes. ErrorHandler
+// should be thread-safe and should not log to the same appender.
+type ErrorHandler func(err error)
+
+// CheckedAppender is implemented by appenders which report write errors.
+// Write errors are passed to ErrorHandler set by SetErrorHandler and
+// counted. Appender and all its clones share ErrorHandler and counter.
+type CheckedAppender interface {
+	EntryAppender
+
+	// TryAppendEntry sends log entry to appender and returns write error.
+	TryAppendEntry(entry *Entry) (err error)
+
+	// SetErrorHandler sets handler of write errors. Nil handler discards
+	// errors.
+	SetErrorHandler(handler ErrorHandler)
+
+	// WriteFailures returns number of failed writes.
+	WriteFailures() (n uint64)
+}
+
+// writeErrors holds error handler and counter of failed writes shared by
+// appender and its clones. Appenders which report write errors embed
+// *writeErrors.
+type writeErrors struct {
+	handler  atomic.Value // ErrorHandler
+	failures atomic.Uint64
+}
+
+// SetErrorHandler sets handler of write errors shared with clones. Nil
+// handler discards errors.
+func (e *writeErrors) SetErrorHandler(handler ErrorHandler) {
+	e.handler.Store(handler)
+}
+
+// WriteFailures returns number of failed writes of appender and its
+// clones.
+func (e *writeErrors) WriteFailures() (n uint64) {
+	return e.failures.Load()
+}
+
+// report counts error and passes it to handler. Nil errors are ignored.
+func (e *writeErrors) report(err error) (res error) {
+	if err == nil {
+		return nil
+	}
+	e.failures.Add(1)
+	if handler, _ := e.handler.Load().(ErrorHandler); handler != nil {
+		handler(err)
+	}
+	return err
+}
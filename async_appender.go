@@ -0,0 +1,307 @@
+package logx
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// OverflowPolicy defines behaviour of AsyncAppender when queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks Append until queue has free slot.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest discards appended entry.
+	OverflowDropNewest
+
+	// OverflowDropOldest discards the oldest entry in queue.
+	OverflowDropOldest
+)
+
+// DefaultDropReportInterval is default minimal interval between
+// "N messages dropped" entries written by AsyncAppender under sustained
+// overflow.
+const DefaultDropReportInterval = time.Second
+
+/*
+AsyncAppender sends entries to wrapped appender from background goroutine.
+Entries are queued in bounded ring buffer. When queue is full, behaviour
+is defined by OverflowPolicy. Dropped entries are reported by WARNING
+entry "logx: N messages dropped".
+
+AsyncAppender and all its clones share the same queue and goroutine.
+Close stops goroutine. Entries appended after Close are sent to wrapped
+appender synchronously.
+
+AsyncAppender does not implement CheckedAppender because entries are
+written after Append returns. To fall back to other appender on write
+errors wrap FallbackAppender with AsyncAppender.
+*/
+type AsyncAppender struct {
+	appender Appender
+	queue    *asyncQueue
+}
+
+// NewAsyncAppender returns AsyncAppender with queue of given size which
+// sends entries to given appender.
+func NewAsyncAppender(appender Appender, size int, policy OverflowPolicy) (a *AsyncAppender) {
+	if size < 1 {
+		size = 1
+	}
+	q := &asyncQueue{
+		root:     appender,
+		policy:   policy,
+		interval: DefaultDropReportInterval,
+		items:    make([]asyncItem, size),
+		progress: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	go q.run()
+	return &AsyncAppender{
+		appender: appender,
+		queue:    q,
+	}
+}
+
+// Clone returns AsyncAppender which sends entries to clone of wrapped
+// appender with given prefix and tags using the same queue.
+func (a *AsyncAppender) Clone(prefix string, tags []string) (a1 Appender) {
+	return &AsyncAppender{
+		appender: a.appender.Clone(prefix, tags),
+		queue:    a.queue,
+	}
+}
+
+// Append queues line with given level.
+func (a *AsyncAppender) Append(level, line string) {
+	l, ok := levelByName(level)
+	item := asyncItem{
+		appender: a.appender,
+		entry: Entry{
+			Level: l,
+			Line:  line,
+			Time:  time.Now(),
+			PC:    callerPC(1),
+		},
+	}
+	if !ok {
+		item.level = level
+	}
+	a.queue.push(item)
+}
+
+// AppendEntry queues entry.
+func (a *AsyncAppender) AppendEntry(entry *Entry) {
+	a.queue.push(asyncItem{
+		appender: a.appender,
+		entry:    *entry,
+	})
+}
+
+// SetDropReportInterval sets minimal interval between "N messages
+// dropped" entries written under sustained overflow. Interval is shared
+// by AsyncAppender and all its clones.
+func (a *AsyncAppender) SetDropReportInterval(interval time.Duration) {
+	a.queue.mu.Lock()
+	a.queue.interval = interval
+	a.queue.mu.Unlock()
+}
+
+// Flush waits until all entries queued before Flush are written and
+// flushes wrapped appender if it implements Flusher.
+func (a *AsyncAppender) Flush(ctx context.Context) (err error) {
+	if err = a.queue.wait(ctx); err != nil {
+		return err
+	}
+	if f, ok := a.appender.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// Close writes all queued entries and stops background goroutine.
+func (a *AsyncAppender) Close() (err error) {
+	a.queue.close()
+	return nil
+}
+
+type asyncItem struct {
+	appender Appender
+	entry    Entry
+
+	// level is set for lines with unknown level names
+	level string
+}
+
+func (i *asyncItem) write() {
+	if i.level == "" {
+		safeAppend(i.appender, &i.entry)
+		return
+	}
+	safeAppendLine(i.appender, i.level, i.entry.Line)
+}
+
+type asyncQueue struct {
+	root   Appender
+	policy OverflowPolicy
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []asyncItem
+	head     int
+	size     int
+	closed   bool
+
+	// pushed and written are counters of entries used by Flush
+	pushed   uint64
+	written  uint64
+	progress chan struct{}
+
+	dropped    uint64
+	reportedAt time.Time
+	interval   time.Duration
+
+	done chan struct{}
+}
+
+func (q *asyncQueue) push(item asyncItem) {
+	q.mu.Lock()
+	for q.policy == OverflowBlock && q.size == len(q.items) && !q.closed {
+		q.notFull.Wait()
+	}
+	if q.closed {
+		q.mu.Unlock()
+		item.write()
+		return
+	}
+	if q.size == len(q.items) {
+		q.dropped++
+		if q.policy == OverflowDropNewest {
+			q.mu.Unlock()
+			return
+		}
+		// drop oldest
+		q.items[q.head] = asyncItem{}
+		q.head = (q.head + 1) % len(q.items)
+		q.size--
+		q.written++
+	}
+	q.items[(q.head+q.size)%len(q.items)] = item
+	q.size++
+	q.pushed++
+	q.notEmpty.Signal()
+	q.mu.Unlock()
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	batch := make([]asyncItem, 0, len(q.items))
+	for {
+		q.mu.Lock()
+		if q.size == 0 && q.dropped > 0 {
+			// overflow is over
+			dropped := q.takeDropped(true)
+			q.mu.Unlock()
+			q.reportDropped(dropped)
+			continue
+		}
+		for q.size == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if q.size == 0 && q.closed {
+			dropped := q.takeDropped(true)
+			q.mu.Unlock()
+			q.reportDropped(dropped)
+			return
+		}
+		for q.size > 0 {
+			batch = append(batch, q.items[q.head])
+			q.items[q.head] = asyncItem{}
+			q.head = (q.head + 1) % len(q.items)
+			q.size--
+		}
+		dropped := q.takeDropped(false)
+		q.notFull.Broadcast()
+		q.mu.Unlock()
+
+		q.reportDropped(dropped)
+		for i := range batch {
+			batch[i].write()
+			batch[i] = asyncItem{}
+		}
+
+		q.mu.Lock()
+		q.written += uint64(len(batch))
+		close(q.progress)
+		q.progress = make(chan struct{})
+		q.mu.Unlock()
+		batch = batch[:0]
+	}
+}
+
+// takeDropped returns number of dropped entries to report. Under
+// sustained overflow dropped entries are reported at most once per
+// drop report interval.
+func (q *asyncQueue) takeDropped(force bool) (n uint64) {
+	if q.dropped == 0 {
+		return 0
+	}
+	now := time.Now()
+	if !force && now.Sub(q.reportedAt) < q.interval {
+		return 0
+	}
+	n, q.dropped, q.reportedAt = q.dropped, 0, now
+	return n
+}
+
+func (q *asyncQueue) reportDropped(n uint64) {
+	if n == 0 {
+		return
+	}
+	safeAppend(q.root, &Entry{
+		Level: LevelWarning,
+		Line:  "logx: " + strconv.FormatUint(n, 10) + " messages dropped",
+		Time:  time.Now(),
+	})
+}
+
+// wait waits until all entries pushed before call are written.
+func (q *asyncQueue) wait(ctx context.Context) (err error) {
+	q.mu.Lock()
+	target := q.pushed
+	for q.written < target && !q.closed {
+		progress := q.progress
+		q.mu.Unlock()
+		select {
+		case <-progress:
+		case <-q.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		q.mu.Lock()
+	}
+	closed := q.closed
+	q.mu.Unlock()
+	if closed {
+		<-q.done
+	}
+	return nil
+}
+
+func (q *asyncQueue) close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		q.notEmpty.Broadcast()
+		q.notFull.Broadcast()
+	}
+	q.mu.Unlock()
+	<-q.done
+}
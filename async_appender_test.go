@@ -0,0 +1,122 @@
+package logx_test
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+// gateAppender blocks writes until gate is closed.
+type gateAppender struct {
+	*logx.TextAppender
+	entered chan struct{}
+	gate    chan struct{}
+}
+
+func (a gateAppender) Clone(prefix string, tags []string) logx.Appender {
+	return gateAppender{
+		TextAppender: a.TextAppender.Clone(prefix, tags).(*logx.TextAppender),
+		entered:      a.entered,
+		gate:         a.gate,
+	}
+}
+
+func (a gateAppender) AppendEntry(entry *logx.Entry) {
+	select {
+	case a.entered <- struct{}{}:
+	default:
+	}
+	<-a.gate
+	a.TextAppender.AppendEntry(entry)
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestAsyncAppender(t *testing.T) {
+	var buf syncBuffer
+	a := logx.NewAsyncAppender(logx.NewTextAppender(&buf, logx.Lshortfile), 16, logx.OverflowBlock)
+	l := logx.NewLog(a, "test")
+	for i := 0; i < 100; i++ {
+		l.Notice(i)
+	}
+	assert.NoError(t, l.Flush(context.Background()))
+
+	var expect string
+	for i := 0; i < 100; i++ {
+		expect += "NOTICE test async_appender_test.go:60 " + strconv.Itoa(i) + "\n"
+	}
+	assert.Equal(t, expect, buf.String())
+	assert.NoError(t, a.Close())
+
+	l.Notice("closed")
+	assert.Contains(t, buf.String(), "closed")
+}
+
+func testAsyncOverflow(t *testing.T, policy logx.OverflowPolicy, expect string) {
+	var buf syncBuffer
+	entered := make(chan struct{}, 1)
+	gate := make(chan struct{})
+	a := logx.NewAsyncAppender(gateAppender{
+		TextAppender: logx.NewTextAppender(&buf, 0),
+		entered:      entered,
+		gate:         gate,
+	}, 2, policy)
+	l := logx.NewLog(a, "")
+
+	// first entry blocks worker
+	l.Notice("0")
+	<-entered
+	for _, v := range []string{"1", "2", "3", "4"} {
+		l.Notice(v)
+	}
+	close(gate)
+	assert.NoError(t, a.Close())
+	assert.Equal(t, expect, buf.String())
+}
+
+func TestAsyncAppender_DropNewest(t *testing.T) {
+	testAsyncOverflow(t, logx.OverflowDropNewest,
+		"NOTICE 0\nWARNING logx: 2 messages dropped\nNOTICE 1\nNOTICE 2\n")
+}
+
+func TestAsyncAppender_DropOldest(t *testing.T) {
+	testAsyncOverflow(t, logx.OverflowDropOldest,
+		"NOTICE 0\nWARNING logx: 2 messages dropped\nNOTICE 3\nNOTICE 4\n")
+}
+
+func TestAsyncAppender_SetDropReportInterval(t *testing.T) {
+	var buf syncBuffer
+	a := logx.NewAsyncAppender(logx.NewTextAppender(&buf, 0), 1, logx.OverflowDropNewest)
+	l := logx.NewLog(a, "")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			l.Notice("entry")
+		}
+	}()
+	a.Clone("clone", nil).(*logx.AsyncAppender).SetDropReportInterval(0)
+	<-done
+	assert.NoError(t, a.Close())
+	assert.Contains(t, buf.String(), "NOTICE entry")
+}
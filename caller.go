@@ -0,0 +1,155 @@
+package logx
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	maxCallerDepth = 32
+
+	// maxStackDepth is maximum number of captured stack frames.
+	maxStackDepth = 64
+)
+
+var (
+	helpers    sync.Map
+	hasHelpers int32
+)
+
+// Helper marks the calling function as logging helper. Like
+// testing.T.Helper, helper functions are skipped when call site of log
+// entry is reported. Helper may be called simultaneously from multiple
+// goroutines.
+func Helper() {
+	var pcs [1]uintptr
+	if runtime.Callers(2, pcs[:]) == 0 {
+		return
+	}
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	if _, loaded := helpers.LoadOrStore(frame.Function, struct{}{}); !loaded {
+		atomic.StoreInt32(&hasHelpers, 1)
+	}
+}
+
+// callerPC returns program counter of call site skipping given number of
+// frames above function which calls callerPC and all helper functions.
+func callerPC(skip int) (pc uintptr) {
+	if atomic.LoadInt32(&hasHelpers) == 0 {
+		var pcs [1]uintptr
+		if runtime.Callers(skip+2, pcs[:]) == 0 {
+			return 0
+		}
+		return pcs[0]
+	}
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	for i := 0; i < n; i++ {
+		frame, _ := runtime.CallersFrames(pcs[i : i+1]).Next()
+		if !isHelper(frame.Function) {
+			return pcs[i]
+		}
+	}
+	if n > 0 {
+		return pcs[n-1]
+	}
+	return 0
+}
+
+func isHelper(function string) (ok bool) {
+	if atomic.LoadInt32(&hasHelpers) == 0 {
+		return false
+	}
+	_, ok = helpers.Load(function)
+	return ok
+}
+
+// captureStack returns call stack starting from frame with given program
+// counter. Frames of logx and helpers above call site are excluded.
+func captureStack(pc uintptr) (stack []uintptr) {
+	if pc == 0 {
+		return nil
+	}
+	var pcs [maxCallerDepth + maxStackDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	for i := 0; i < n; i++ {
+		if pcs[i] != pc {
+			continue
+		}
+		end := i + maxStackDepth
+		if end > n {
+			end = n
+		}
+		return append([]uintptr(nil), pcs[i:end]...)
+	}
+	return nil
+}
+
+// stackFrames returns frames of stack as "function file:line" strings.
+func stackFrames(stack []uintptr) (res []string) {
+	if len(stack) == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "runtime.goexit" {
+			res = append(res, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+		if !more {
+			return res
+		}
+	}
+}
+
+// writeStack writes stack as indented continuation lines:
+//
+//	function
+//		file:line
+func writeStack(buf *bytes.Buffer, stack []uintptr) {
+	if len(stack) == 0 {
+		return
+	}
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "runtime.goexit" {
+			buf.WriteString("\n\t")
+			buf.WriteString(frame.Function)
+			buf.WriteString("\n\t\t")
+			buf.WriteString(frame.File)
+			buf.WriteByte(':')
+			itoaBuf(buf, frame.Line, -1)
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// writeCaller writes "file:line" of given program counter.
+func writeCaller(buf *bytes.Buffer, flags int, pc uintptr) {
+	file, lineNo := "???", 0
+	if pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		if frame.File != "" {
+			file, lineNo = frame.File, frame.Line
+		}
+	}
+	if flags&Lshortfile != 0 {
+		short := file
+		for i := len(file) - 1; i > 0; i-- {
+			if file[i] == '/' {
+				short = file[i+1:]
+				break
+			}
+		}
+		file = short
+	}
+	buf.WriteString(file)
+	buf.WriteByte(':')
+	itoaBuf(buf, lineNo, -1)
+}
@@ -0,0 +1,44 @@
+package logx_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func noticeWrapper(l *logx.Log, v ...interface{}) {
+	l.WithCallerSkip(1).Notice(v...)
+}
+
+func noticeHelper(l *logx.Log, v ...interface{}) {
+	logx.Helper()
+	l.Notice(v...)
+}
+
+//go:noinline
+func noticeHelperNoInline(l *logx.Log, v ...interface{}) {
+	logx.Helper()
+	noticeHelper(l, v...)
+}
+
+func TestLog_WithCallerSkip(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, logx.Lshortfile), "")
+	noticeWrapper(l, "test")
+	assert.Equal(t, "NOTICE caller_test.go:29 test\n", buf.String())
+}
+
+func TestHelper(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, logx.Lshortfile), "")
+	noticeHelper(l, "inline")
+	noticeHelperNoInline(l, "noinline")
+	l.Notice("direct")
+	assert.Equal(t, ""+
+		"NOTICE caller_test.go:36 inline\n"+
+		"NOTICE caller_test.go:37 noinline\n"+
+		"NOTICE caller_test.go:38 direct\n",
+		buf.String())
+}
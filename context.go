@@ -0,0 +1,200 @@
+package logx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ContextExtractor returns fields extracted from context.
+type ContextExtractor func(ctx context.Context) (fields []Field)
+
+// ContextHook is called for each entry logged by context-aware logging
+// methods before entry is sent to appender.
+type ContextHook func(ctx context.Context, entry *Entry)
+
+type logCtxKey struct{}
+
+var (
+	registryMu sync.Mutex
+	extractors atomic.Value // []ContextExtractor
+	hooks      atomic.Value // []ContextHook
+)
+
+// WithLog returns copy of context which carries given log.
+func WithLog(ctx context.Context, l *Log) (res context.Context) {
+	return context.WithValue(ctx, logCtxKey{}, l)
+}
+
+// FromContext returns log carried by context or default log if context
+// has no log.
+func FromContext(ctx context.Context) (l *Log) {
+	if ctx != nil {
+		if l, ok := ctx.Value(logCtxKey{}).(*Log); ok && l != nil {
+			return l
+		}
+	}
+	return Default()
+}
+
+/*
+RegisterContextExtractor registers function which extracts fields from
+context passed to context-aware logging methods like NoticeContext.
+Extracted fields are added after fields of log in order of registration.
+RegisterContextExtractor is usually called on program initialisation:
+
+	logx.RegisterContextExtractor(func(ctx context.Context) []logx.Field {
+		if id, ok := ctx.Value(requestIDKey).(string); ok {
+			return []logx.Field{{Key: "request_id", Value: id}}
+		}
+		return nil
+	})
+*/
+func RegisterContextExtractor(extractor ContextExtractor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	current, _ := extractors.Load().([]ContextExtractor)
+	next := make([]ContextExtractor, 0, len(current)+1)
+	next = append(next, current...)
+	extractors.Store(append(next, extractor))
+}
+
+// RegisterContextHook registers hook which is called for each entry logged
+// by context-aware logging methods. Hooks must not modify entry.
+func RegisterContextHook(hook ContextHook) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	current, _ := hooks.Load().([]ContextHook)
+	next := make([]ContextHook, 0, len(current)+1)
+	next = append(next, current...)
+	hooks.Store(append(next, hook))
+}
+
+// ContextValue returns extractor which adds field with given key if
+// context has non-nil value for ctxKey.
+func ContextValue(key string, ctxKey interface{}) (extractor ContextExtractor) {
+	return func(ctx context.Context) (fields []Field) {
+		if v := ctx.Value(ctxKey); v != nil {
+			return []Field{{Key: key, Value: v}}
+		}
+		return nil
+	}
+}
+
+// NoticeContext logs value with NOTICE severity level and fields
+// extracted from context.
+func (l *Log) NoticeContext(ctx context.Context, v ...interface{}) {
+	if !l.level.enabled(LevelNotice) {
+		return
+	}
+	l.appendContext(ctx, LevelNotice, fmt.Sprint(v...))
+}
+
+// NoticefContext logs formatted value with NOTICE severity level and
+// fields extracted from context.
+func (l *Log) NoticefContext(ctx context.Context, format string, v ...interface{}) {
+	if !l.level.enabled(LevelNotice) {
+		return
+	}
+	l.appendContext(ctx, LevelNotice, fmt.Sprintf(format, v...))
+}
+
+// WarningContext logs value with WARNING severity level and fields
+// extracted from context.
+func (l *Log) WarningContext(ctx context.Context, v ...interface{}) {
+	if !l.level.enabled(LevelWarning) {
+		return
+	}
+	l.appendContext(ctx, LevelWarning, fmt.Sprint(v...))
+}
+
+// WarningfContext logs formatted value with WARNING severity level and
+// fields extracted from context.
+func (l *Log) WarningfContext(ctx context.Context, format string, v ...interface{}) {
+	if !l.level.enabled(LevelWarning) {
+		return
+	}
+	l.appendContext(ctx, LevelWarning, fmt.Sprintf(format, v...))
+}
+
+// ErrorContext logs value with ERROR severity level and fields extracted
+// from context.
+func (l *Log) ErrorContext(ctx context.Context, v ...interface{}) {
+	if !l.level.enabled(LevelError) {
+		return
+	}
+	l.appendContext(ctx, LevelError, fmt.Sprint(v...))
+}
+
+// ErrorfContext logs formatted value with ERROR severity level and fields
+// extracted from context.
+func (l *Log) ErrorfContext(ctx context.Context, format string, v ...interface{}) {
+	if !l.level.enabled(LevelError) {
+		return
+	}
+	l.appendContext(ctx, LevelError, fmt.Sprintf(format, v...))
+}
+
+// CriticalContext logs value with CRITICAL severity level and fields
+// extracted from context.
+func (l *Log) CriticalContext(ctx context.Context, v ...interface{}) {
+	if !l.level.enabled(LevelCritical) {
+		return
+	}
+	l.appendContext(ctx, LevelCritical, fmt.Sprint(v...))
+}
+
+// CriticalfContext logs formatted value with CRITICAL severity level and
+// fields extracted from context.
+func (l *Log) CriticalfContext(ctx context.Context, format string, v ...interface{}) {
+	if !l.level.enabled(LevelCritical) {
+		return
+	}
+	l.appendContext(ctx, LevelCritical, fmt.Sprintf(format, v...))
+}
+
+// appendContext sends line with fields extracted from context to
+// appender with call site of caller of logging method.
+func (l *Log) appendContext(ctx context.Context, level Level, line string) {
+	entry := &Entry{
+		Level:  level,
+		Line:   line,
+		Fields: l.contextFields(ctx),
+		Time:   time.Now(),
+		PC:     callerPC(2 + l.callerSkip),
+	}
+	entry.Stack = l.entryStack(level, entry.PC)
+	if ctx != nil {
+		fns, _ := hooks.Load().([]ContextHook)
+		for _, fn := range fns {
+			fn(ctx, entry)
+		}
+	}
+	appendEntry(l.appender, entry)
+}
+
+// contextFields returns fields of log followed by fields extracted from
+// context.
+func (l *Log) contextFields(ctx context.Context) (fields []Field) {
+	fns, _ := extractors.Load().([]ContextExtractor)
+	if ctx == nil || len(fns) == 0 {
+		return l.fields
+	}
+	fields = l.fields
+	extended := false
+	for _, fn := range fns {
+		extracted := fn(ctx)
+		if len(extracted) == 0 {
+			continue
+		}
+		if !extended {
+			// never append to fields shared with log
+			fields = append(make([]Field, 0, len(l.fields)+len(extracted)), l.fields...)
+			extended = true
+		}
+		fields = append(fields, extracted...)
+	}
+	return fields
+}
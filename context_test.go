@@ -0,0 +1,48 @@
+package logx_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+type requestIDKey struct{}
+
+func init() {
+	logx.RegisterContextExtractor(logx.ContextValue("request_id", requestIDKey{}))
+}
+
+func TestFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, 0), "test")
+	ctx := logx.WithLog(context.Background(), l)
+	assert.Equal(t, l, logx.FromContext(ctx))
+	assert.Equal(t, logx.GetLog("").Prefix(), logx.FromContext(context.Background()).Prefix())
+	assert.NotNil(t, logx.FromContext(context.Background()))
+}
+
+func TestLog_Context(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, logx.Lshortfile), "test").With("a", 1)
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "r1")
+
+	l.NoticeContext(ctx, "with")
+	l.WarningfContext(context.Background(), "%s", "without")
+	l.ErrorContext(ctx, "again")
+	assert.Equal(t, "NOTICE test context_test.go:32 with a=1 request_id=r1\n"+
+		"WARNING test context_test.go:33 without a=1\n"+
+		"ERROR test context_test.go:34 again a=1 request_id=r1\n", buf.String())
+	assert.Equal(t, []logx.Field{{Key: "a", Value: 1}}, l.Fields())
+}
+
+func TestLog_Context_Level(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, 0), "")
+	l.SetLevel(logx.LevelError)
+	l.WarningContext(context.Background(), "skip")
+	l.CriticalfContext(context.Background(), "%d", 1)
+	assert.Equal(t, "CRITICAL 1\n", buf.String())
+}
@@ -2,6 +2,8 @@
 
 package logx
 
+import "context"
+
 // Debug logs value with DEBUG severity level only
 // if "debug" tag is provided on build.
 func (*Log) Debug(v ...interface{}) {}
@@ -9,3 +11,11 @@ func (*Log) Debug(v ...interface{}) {}
 // Debugf logs formatted value with DEBUG severity level only
 // if "debug" tag is provided on build.
 func (*Log) Debugf(format string, v ...interface{}) {}
+
+// DebugContext logs value with DEBUG severity level and fields
+// extracted from context only if "debug" tag is provided on build.
+func (*Log) DebugContext(ctx context.Context, v ...interface{}) {}
+
+// DebugfContext logs formatted value with DEBUG severity level and fields
+// extracted from context only if "debug" tag is provided on build.
+func (*Log) DebugfContext(ctx context.Context, format string, v ...interface{}) {}
@@ -3,21 +3,42 @@
 package logx
 
 import (
+	"context"
 	"fmt"
 )
 
-const (
-	lDebug = "DEBUG"
-)
-
 // Debug logs value with DEBUG severity level only
 // if "debug" tag is provided on build.
 func (l *Log) Debug(v ...interface{}) {
-	l.appender.Append(lDebug, fmt.Sprint(v...))
+	if !l.level.enabled(LevelDebug) {
+		return
+	}
+	l.append(LevelDebug, fmt.Sprint(v...))
 }
 
 // Debugf logs formatted value with DEBUG severity level only
 // if "debug" tag is provided on build.
 func (l *Log) Debugf(format string, v ...interface{}) {
-	l.appender.Append(lDebug, fmt.Sprintf(format, v...))
+	if !l.level.enabled(LevelDebug) {
+		return
+	}
+	l.append(LevelDebug, fmt.Sprintf(format, v...))
+}
+
+// DebugContext logs value with DEBUG severity level and fields
+// extracted from context only if "debug" tag is provided on build.
+func (l *Log) DebugContext(ctx context.Context, v ...interface{}) {
+	if !l.level.enabled(LevelDebug) {
+		return
+	}
+	l.appendContext(ctx, LevelDebug, fmt.Sprint(v...))
+}
+
+// DebugfContext logs formatted value with DEBUG severity level and fields
+// extracted from context only if "debug" tag is provided on build.
+func (l *Log) DebugfContext(ctx context.Context, format string, v ...interface{}) {
+	if !l.level.enabled(LevelDebug) {
+		return
+	}
+	l.appendContext(ctx, LevelDebug, fmt.Sprintf(format, v...))
 }
@@ -0,0 +1,236 @@
+package logx
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Field is typed key/value pair attached to log entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Entry is single log entry passed to EntryAppender.
+type Entry struct {
+	// Level is severity level of entry.
+	Level Level
+
+	// Line is formatted log message.
+	Line string
+
+	// Fields are key/value pairs attached to log.
+	Fields []Field
+
+	// Time is time of entry. Zero time means that time is unknown and
+	// appenders omit it.
+	Time time.Time
+
+	// PC is program counter of call site. Zero PC means that call site
+	// is unknown.
+	PC uintptr
+
+	// Stack is program counters of call stack starting from call site.
+	// Stack is captured for logs created with WithStack or taken from
+	// error passed to WithError, Err or Errf.
+	Stack []uintptr
+}
+
+// writeFields writes fields to buffer as " key=value" pairs.
+func writeFields(buf *bytes.Buffer, fields []Field) {
+	for _, field := range fields {
+		buf.WriteByte(' ')
+		writeKey(buf, field.Key)
+		buf.WriteByte('=')
+		writeValue(buf, field.Value)
+	}
+}
+
+// reservedKeyPrefix is prepended to keys of fields which collide with
+// keys written by JSON and logfmt appenders.
+const reservedKeyPrefix = "fields."
+
+// isReservedKey reports whether key is written by JSON and logfmt
+// appenders themselves.
+func isReservedKey(key string) (ok bool) {
+	switch key {
+	case "ts", "level", "prefix", "tags", "caller", "msg", "stack":
+		return true
+	}
+	return false
+}
+
+// writeKey writes field key to buffer. Characters which need quoting in
+// values are replaced with "_" because logfmt keys can't be quoted.
+func writeKey(buf *bytes.Buffer, key string) {
+	if !needsQuote(key) {
+		buf.WriteString(key)
+		return
+	}
+	if key == "" {
+		buf.WriteByte('_')
+		return
+	}
+	for _, c := range key {
+		if c <= ' ' || c == '=' || c == '"' || c == utf8.RuneError || !unicode.IsPrint(c) {
+			c = '_'
+		}
+		buf.WriteRune(c)
+	}
+}
+
+// rawValue returns unquoted string representation of field value.
+func rawValue(value interface{}) (res string) {
+	switch v := value.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	buf := bufferPool.Get().(*bytes.Buffer)
+	writeValue(buf, value)
+	res = buf.String()
+	buf.Reset()
+	bufferPool.Put(buf)
+	return res
+}
+
+// writeValue writes value to buffer. Strings which contain whitespace,
+// quotes or equal signs are quoted.
+func writeValue(buf *bytes.Buffer, value interface{}) {
+	var scratch [64]byte
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("<nil>")
+	case string:
+		writeString(buf, v)
+	case bool:
+		buf.Write(strconv.AppendBool(scratch[:0], v))
+	case int:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int8:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int16:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int32:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int64:
+		buf.Write(strconv.AppendInt(scratch[:0], v, 10))
+	case uint:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint8:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint16:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint32:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint64:
+		buf.Write(strconv.AppendUint(scratch[:0], v, 10))
+	case float32:
+		buf.Write(strconv.AppendFloat(scratch[:0], float64(v), 'g', -1, 32))
+	case float64:
+		buf.Write(strconv.AppendFloat(scratch[:0], v, 'g', -1, 64))
+	case error:
+		writeString(buf, v.Error())
+	case fmt.Stringer:
+		writeString(buf, v.String())
+	default:
+		writeString(buf, fmt.Sprint(v))
+	}
+}
+
+// writeString writes string to buffer. Strings which need quoting are
+// quoted with only quotes, backslashes, newlines, carriage returns and
+// tabs escaped as logfmt expects. Invalid UTF-8 sequences are replaced
+// with U+FFFD.
+func writeString(buf *bytes.Buffer, s string) {
+	if !needsQuote(s) {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				buf.WriteString(s[start:i])
+				buf.WriteString("\ufffd")
+				start = i + size
+			}
+			i += size
+			continue
+		}
+		var esc string
+		switch c {
+		case '"':
+			esc = `\"`
+		case '\\':
+			esc = `\\`
+		case '\n':
+			esc = `\n`
+		case '\r':
+			esc = `\r`
+		case '\t':
+			esc = `\t`
+		default:
+			i++
+			continue
+		}
+		buf.WriteString(s[start:i])
+		buf.WriteString(esc)
+		i++
+		start = i
+	}
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
+
+func needsQuote(s string) (ok bool) {
+	if s == "" {
+		return true
+	}
+	for _, c := range s {
+		if c <= ' ' || c == '=' || c == '"' || c == utf8.RuneError || !unicode.IsPrint(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendEntry sends entry to appender. Appenders which do not implement
+// EntryAppender receive line with fields rendered as "key=value" pairs.
+func appendEntry(appender Appender, entry *Entry) {
+	if a, ok := appender.(EntryAppender); ok {
+		a.AppendEntry(entry)
+		return
+	}
+	line := entry.Line
+	if len(entry.Fields) > 0 || len(entry.Stack) > 0 {
+		buf := bufferPool.Get().(*bytes.Buffer)
+		buf.WriteString(line)
+		writeFields(buf, entry.Fields)
+		writeStack(buf, entry.Stack)
+		line = buf.String()
+		buf.Reset()
+		bufferPool.Put(buf)
+	}
+	appender.Append(entry.Level.String(), line)
+}
+
+// tryAppendEntry sends entry to appender and returns write error if
+// appender implements CheckedAppender.
+func tryAppendEntry(appender Appender, entry *Entry) (err error) {
+	if a, ok := appender.(CheckedAppender); ok {
+		return a.TryAppendEntry(entry)
+	}
+	appendEntry(appender, entry)
+	return nil
+}
@@ -0,0 +1,212 @@
+package logx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// ErrorKey is key of field with error message.
+	ErrorKey = "error"
+
+	// ErrorChainKey is key of field with messages of wrapped errors.
+	ErrorChainKey = "error_chain"
+
+	// maxErrorDepth limits walking of wrapped errors.
+	maxErrorDepth = 32
+)
+
+// ErrorChain is messages of all errors wrapped by error. Message of error
+// itself is not included. Errors wrapped by errors.Join are included
+// depth-first. ErrorChain is written
+// as string in text formats and as array in JSON.
+type ErrorChain []string
+
+// String returns messages joined with "; ".
+func (c ErrorChain) String() (res string) {
+	return strings.Join(c, "; ")
+}
+
+// MarshalJSON encodes chain as JSON array.
+func (c ErrorChain) MarshalJSON() (data []byte, err error) {
+	return json.Marshal([]string(c))
+}
+
+type errorType struct {
+	key string
+	typ reflect.Type
+}
+
+var (
+	errorTypesMu sync.Mutex
+	errorTypes   atomic.Value // []errorType
+)
+
+func init() {
+	RegisterErrorType("net_error", (*net.OpError)(nil))
+	RegisterErrorType("path_error", (*os.PathError)(nil))
+	RegisterErrorType("syscall_error", (*os.SyscallError)(nil))
+}
+
+/*
+RegisterErrorType registers error type detected by errors.As in errors
+passed to WithError, Err and Errf. First error of given type in chain is
+added as field with given key. Sample is value of error type:
+
+	logx.RegisterErrorType("http_error", (*HTTPError)(nil))
+
+*net.OpError, *os.PathError and *os.SyscallError are registered by
+default as "net_error", "path_error" and "syscall_error".
+*/
+func RegisterErrorType(key string, sample error) {
+	errorTypesMu.Lock()
+	defer errorTypesMu.Unlock()
+	current, _ := errorTypes.Load().([]errorType)
+	next := make([]errorType, 0, len(current)+1)
+	next = append(next, current...)
+	errorTypes.Store(append(next, errorType{
+		key: key,
+		typ: reflect.TypeOf(sample),
+	}))
+}
+
+/*
+WithError returns copy of log with fields describing error:
+
+	error           message of error
+	error_chain     messages of wrapped errors if error wraps any
+	<registered>    errors of types registered by RegisterErrorType
+
+If error or any wrapped error has StackTrace method which returns slice
+of program counters (like errors of github.com/pkg/errors), the deepest
+stack is written with entries like stack captured by WithStack.
+WithError returns log as is if error is nil. Returned log shares runtime
+level with parent.
+*/
+func (l *Log) WithError(err error) (res *Log) {
+	if err == nil {
+		return l
+	}
+	res = l.WithFields(errorFields(err)...)
+	if stack := errorStack(err); stack != nil {
+		res.errStack = stack
+	}
+	return res
+}
+
+// Err logs value with ERROR severity level and fields describing error.
+// See WithError.
+func (l *Log) Err(err error, v ...interface{}) {
+	if !l.level.enabled(LevelError) {
+		return
+	}
+	l.WithError(err).appendPC(LevelError, errorLine(err, fmt.Sprint(v...)), callerPC(1+l.callerSkip))
+}
+
+// Errf logs formatted value with ERROR severity level and fields
+// describing error. See WithError.
+func (l *Log) Errf(err error, format string, v ...interface{}) {
+	if !l.level.enabled(LevelError) {
+		return
+	}
+	l.WithError(err).appendPC(LevelError, errorLine(err, fmt.Sprintf(format, v...)), callerPC(1+l.callerSkip))
+}
+
+// errorLine returns line or message of error if line is empty.
+func errorLine(err error, line string) (res string) {
+	if line == "" && err != nil {
+		return err.Error()
+	}
+	return line
+}
+
+func errorFields(err error) (fields []Field) {
+	fields = append(fields, Field{Key: ErrorKey, Value: err.Error()})
+	var chain ErrorChain
+	top := true
+	walkErrors(err, 0, func(e error) {
+		if top {
+			top = false
+			return
+		}
+		chain = append(chain, e.Error())
+	})
+	if len(chain) > 0 {
+		fields = append(fields, Field{Key: ErrorChainKey, Value: chain})
+	}
+	types, _ := errorTypes.Load().([]errorType)
+	for _, t := range types {
+		target := reflect.New(t.typ)
+		if errors.As(err, target.Interface()) {
+			fields = append(fields, Field{Key: t.key, Value: target.Elem().Interface()})
+		}
+	}
+	return fields
+}
+
+// walkErrors calls fn for error and all wrapped errors depth-first.
+func walkErrors(err error, depth int, fn func(err error)) {
+	if err == nil || depth >= maxErrorDepth {
+		return
+	}
+	fn(err)
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range e.Unwrap() {
+			walkErrors(wrapped, depth+1, fn)
+		}
+	case interface{ Unwrap() error }:
+		walkErrors(e.Unwrap(), depth+1, fn)
+	}
+}
+
+// errorStack returns stack of the deepest error in chain which has
+// StackTrace method.
+func errorStack(err error) (stack []uintptr) {
+	walkErrors(err, 0, func(e error) {
+		if s := stackTrace(e); s != nil {
+			stack = s
+		}
+	})
+	return stack
+}
+
+// stackTracer is implemented by errors which carry stack.
+type stackTracer interface {
+	StackTrace() []uintptr
+}
+
+// stackTrace returns stack of error. Besides StackTrace() []uintptr,
+// StackTrace methods returning slice of uintptr-based values like
+// errors.StackTrace of github.com/pkg/errors are accepted. Typed nil
+// errors have no stack.
+func stackTrace(err error) (stack []uintptr) {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	if s, ok := err.(stackTracer); ok {
+		return s.StackTrace()
+	}
+	m := v.MethodByName("StackTrace")
+	if !m.IsValid() {
+		return nil
+	}
+	t := m.Type()
+	if t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Slice || t.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	out := m.Call(nil)[0]
+	stack = make([]uintptr, out.Len())
+	for i := range stack {
+		stack[i] = uintptr(out.Index(i).Uint())
+	}
+	return stack
+}
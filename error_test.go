@@ -0,0 +1,140 @@
+package logx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+type tracedError struct {
+	msg   string
+	stack []uintptr
+}
+
+func newTracedError(msg string) (err error) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &tracedError{msg: msg, stack: pcs[:n]}
+}
+
+func (e *tracedError) Error() string {
+	if e == nil {
+		return "nil traced"
+	}
+	return e.msg
+}
+
+func (e *tracedError) StackTrace() []uintptr {
+	return e.stack
+}
+
+// Frame, StackTrace and pkgError mimic github.com/pkg/errors.
+type Frame uintptr
+
+type StackTrace []Frame
+
+type pkgError struct {
+	msg   string
+	stack []uintptr
+}
+
+func newPkgError(msg string) (err error) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &pkgError{msg: msg, stack: pcs[:n]}
+}
+
+func (e *pkgError) Error() string {
+	return e.msg
+}
+
+func (e *pkgError) StackTrace() StackTrace {
+	st := make(StackTrace, len(e.stack))
+	for i, pc := range e.stack {
+		st[i] = Frame(pc)
+	}
+	return st
+}
+
+func init() {
+	logx.RegisterErrorType("custom_error", customError{})
+}
+
+func TestLog_Err(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, 0), "test")
+	pathErr := &os.PathError{Op: "open", Path: "/nonexistent/file", Err: os.ErrNotExist}
+	l.Err(fmt.Errorf("load: %w", pathErr), "failed")
+	l.Errf(errors.New("plain"), "failed %d", 2)
+	l.Err(errors.New("only"))
+	l.Err(nil, "no error")
+
+	assert.Equal(t, `ERROR test failed error="load: open /nonexistent/file: file does not exist" error_chain="open /nonexistent/file: file does not exist; file does not exist" path_error="open /nonexistent/file: file does not exist"`+"\n"+
+		"ERROR test failed 2 error=plain\n"+
+		"ERROR test only error=only\n"+
+		"ERROR test no error\n", buf.String())
+}
+
+func TestLog_WithError_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewJSONAppender(&buf, 0), "")
+	l.WithError(errors.Join(errors.New("one"), fmt.Errorf("two: %w", errors.New("three")))).Warning("joined")
+	assert.Equal(t, `{"level":"WARNING","msg":"joined","error":"one\ntwo: three","error_chain":["one","two: three","three"]}`+"\n", buf.String())
+}
+
+type customError struct{}
+
+func (customError) Error() string {
+	return "custom"
+}
+
+func TestRegisterErrorType(t *testing.T) {
+	var buf bytes.Buffer
+	logx.NewLog(logx.NewLogfmtAppender(&buf, 0), "").Err(fmt.Errorf("wrap: %w", customError{}), "failed")
+	assert.Equal(t, `level=error msg=failed error="wrap: custom" error_chain=custom custom_error=custom`+"\n", buf.String())
+}
+
+func TestLog_WithError_Stack(t *testing.T) {
+	var buf bytes.Buffer
+	err := newTracedError("traced")
+	logx.NewLog(logx.NewTextAppender(&buf, 0), "test").Err(fmt.Errorf("wrap: %w", err), "failed")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Equal(t, `ERROR test failed error="wrap: traced" error_chain=traced`, lines[0])
+	assert.Equal(t, "\tgithub.com/akaspin/logx_test.TestLog_WithError_Stack", lines[1])
+	assert.True(t, strings.HasSuffix(lines[2], "/error_test.go:107"), lines[2])
+
+	buf.Reset()
+	logx.NewLog(logx.NewJSONAppender(&buf, 0), "").WithError(err).Notice("traced")
+	var v struct {
+		Stack []string `json:"stack"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &v))
+	assert.Regexp(t, `^github\.com/akaspin/logx_test\.TestLog_WithError_Stack /.*/error_test\.go:107$`, v.Stack[0])
+}
+
+func TestLog_WithError_NilStack(t *testing.T) {
+	var buf bytes.Buffer
+	var err *tracedError
+	logx.NewLog(logx.NewTextAppender(&buf, 0), "test").Err(fmt.Errorf("wrap: %w", err), "failed")
+	assert.Equal(t, `ERROR test failed error="wrap: nil traced" error_chain="nil traced"`+"\n", buf.String())
+}
+
+func TestLog_WithError_PkgStack(t *testing.T) {
+	var buf bytes.Buffer
+	err := newPkgError("pkg")
+	logx.NewLog(logx.NewTextAppender(&buf, 0), "test").Err(fmt.Errorf("wrap: %w", err), "failed")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Equal(t, `ERROR test failed error="wrap: pkg" error_chain=pkg`, lines[0])
+	assert.Equal(t, "\tgithub.com/akaspin/logx_test.TestLog_WithError_PkgStack", lines[1])
+	assert.True(t, strings.HasSuffix(lines[2], "/error_test.go:133"), lines[2])
+}
@@ -0,0 +1,11 @@
+package logx
+
+import "time"
+
+// OpenRotatingFileClock opens RotatingFile which uses given clock.
+func OpenRotatingFileClock(path string, opts RotateOptions, now func() time.Time) (f *RotatingFile, err error) {
+	return openRotatingFile(path, opts, now)
+}
+
+// RotateTimeFormat is format of timestamps of rotated files.
+const RotateTimeFormat = rotateTimeFormat
@@ -0,0 +1,157 @@
+package logx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*
+FallbackAppender writes entries to primary appender and switches to
+secondary appender when primary fails maxFailures times in a row. Entry
+which failed to write to primary is written to secondary. After switch
+FallbackAppender tries primary again once per retry interval and switches
+back on first successful write:
+
+	primary := logx.NewJSONAppender(file, logx.LstdFlags)
+	a := logx.NewFallbackAppender(primary,
+		logx.NewTextAppender(os.Stderr, logx.LstdFlags), 3, time.Minute)
+
+Failures are detected only for primary appenders which implement
+CheckedAppender. MultiAppender and LevelFilter pass write errors of
+wrapped appenders through. AsyncAppender writes in background and does
+not report write errors, so wrap FallbackAppender with AsyncAppender and
+not vice versa:
+
+	a := logx.NewAsyncAppender(logx.NewFallbackAppender(primary,
+		secondary, 3, time.Minute), 1024, logx.OverflowBlock)
+
+FallbackAppender and all its clones share the state.
+*/
+type FallbackAppender struct {
+	primary   Appender
+	secondary Appender
+	state     *fallbackState
+}
+
+// NewFallbackAppender returns FallbackAppender with given primary and
+// secondary appenders.
+func NewFallbackAppender(primary, secondary Appender, maxFailures int, retry time.Duration) (a *FallbackAppender) {
+	if maxFailures < 1 {
+		maxFailures = 1
+	}
+	return &FallbackAppender{
+		primary:   primary,
+		secondary: secondary,
+		state: &fallbackState{
+			maxFailures: maxFailures,
+			retry:       retry,
+		},
+	}
+}
+
+// Clone returns FallbackAppender with clones of primary and secondary
+// appenders.
+func (a *FallbackAppender) Clone(prefix string, tags []string) (a1 Appender) {
+	return &FallbackAppender{
+		primary:   a.primary.Clone(prefix, tags),
+		secondary: a.secondary.Clone(prefix, tags),
+		state:     a.state,
+	}
+}
+
+// Append writes line with given level. Lines with unknown level names
+// are sent to current appender without error checking.
+func (a *FallbackAppender) Append(level, line string) {
+	l, ok := levelByName(level)
+	if !ok {
+		if a.state.usePrimary() {
+			a.primary.Append(level, line)
+		} else {
+			a.secondary.Append(level, line)
+		}
+		return
+	}
+	a.AppendEntry(&Entry{
+		Level: l,
+		Line:  line,
+		Time:  time.Now(),
+		PC:    callerPC(1),
+	})
+}
+
+// AppendEntry writes entry to primary appender or to secondary appender
+// if primary fails.
+func (a *FallbackAppender) AppendEntry(entry *Entry) {
+	if a.state.usePrimary() {
+		err := tryAppendEntry(a.primary, entry)
+		a.state.result(err)
+		if err == nil {
+			return
+		}
+	}
+	appendEntry(a.secondary, entry)
+}
+
+// UsingFallback reports whether entries are written to secondary
+// appender.
+func (a *FallbackAppender) UsingFallback() (ok bool) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	return a.state.switched
+}
+
+// Flush flushes primary and secondary appenders which implement Flusher.
+// First error is returned.
+func (a *FallbackAppender) Flush(ctx context.Context) (err error) {
+	for _, appender := range []Appender{a.primary, a.secondary} {
+		if f, ok := appender.(Flusher); ok {
+			if fErr := f.Flush(ctx); fErr != nil && err == nil {
+				err = fErr
+			}
+		}
+	}
+	return err
+}
+
+type fallbackState struct {
+	maxFailures int
+	retry       time.Duration
+
+	mu         sync.Mutex
+	failures   int
+	switched   bool
+	switchedAt time.Time
+}
+
+// usePrimary reports whether entry should be written to primary appender.
+// After switch primary is tried once per retry interval.
+func (s *fallbackState) usePrimary() (ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.switched {
+		return true
+	}
+	if time.Since(s.switchedAt) < s.retry {
+		return false
+	}
+	// probe primary and postpone next probe
+	s.switchedAt = time.Now()
+	return true
+}
+
+// result records result of write to primary appender.
+func (s *fallbackState) result(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.failures = 0
+		s.switched = false
+		return
+	}
+	s.failures++
+	if s.failures >= s.maxFailures && !s.switched {
+		s.switched = true
+		s.switchedAt = time.Now()
+	}
+}
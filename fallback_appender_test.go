@@ -0,0 +1,66 @@
+package logx_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFallbackAppender(t *testing.T) {
+	primary := &brokenWriter{}
+	var secondary bytes.Buffer
+	a := logx.NewFallbackAppender(
+		logx.NewTextAppender(primary, 0),
+		logx.NewTextAppender(&secondary, 0), 2, time.Hour)
+	l := logx.NewLog(a, "test")
+
+	l.Notice("one")
+	primary.broken = true
+	l.Notice("two")
+	assert.False(t, a.UsingFallback())
+	l.Notice("three")
+	assert.True(t, a.UsingFallback())
+	primary.broken = false
+	l.Notice("four")
+
+	assert.Equal(t, "NOTICE test one\n", primary.String())
+	assert.Equal(t, "NOTICE test two\nNOTICE test three\nNOTICE test four\n", secondary.String())
+}
+
+func TestFallbackAppender_Recover(t *testing.T) {
+	primary := &brokenWriter{broken: true}
+	var secondary bytes.Buffer
+	a := logx.NewFallbackAppender(
+		logx.NewTextAppender(primary, 0),
+		logx.NewTextAppender(&secondary, 0), 1, 0)
+	l := logx.NewLog(a, "test")
+
+	l.Notice("one")
+	assert.True(t, a.UsingFallback())
+	l.Notice("two")
+	primary.broken = false
+	l.Notice("three")
+	assert.False(t, a.UsingFallback())
+	l.Notice("four")
+
+	assert.Equal(t, "NOTICE test three\nNOTICE test four\n", primary.String())
+	assert.Equal(t, "NOTICE test one\nNOTICE test two\n", secondary.String())
+}
+
+func TestFallbackAppender_Wrapped(t *testing.T) {
+	primary := &brokenWriter{broken: true}
+	var other, secondary bytes.Buffer
+	a := logx.NewFallbackAppender(
+		logx.NewMultiAppender(
+			logx.NewLevelFilter(logx.NewTextAppender(primary, 0), logx.LevelNotice),
+			logx.NewTextAppender(&other, 0)),
+		logx.NewTextAppender(&secondary, 0), 1, time.Hour)
+	logx.NewLog(a, "test").Notice("one")
+
+	assert.True(t, a.UsingFallback())
+	assert.Equal(t, "NOTICE test one\n", other.String())
+	assert.Equal(t, "NOTICE test one\n", secondary.String())
+}
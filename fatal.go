@@ -0,0 +1,130 @@
+package logx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+var exitHook atomic.Value // func(code int)
+
+func init() {
+	exitHook.Store(os.Exit)
+}
+
+/*
+SetExitFunc atomically replaces function called by Fatal, Fatalf and
+Fatalln of all logs without own function set by WithExitFunc. Nil
+function restores os.Exit. Returned function restores previous function:
+
+	defer logx.SetExitFunc(func(code int) {
+		exited = code
+	})()
+*/
+func SetExitFunc(fn func(code int)) (restore func()) {
+	if fn == nil {
+		fn = os.Exit
+	}
+	prev := exitHook.Swap(fn)
+	return func() {
+		exitHook.Store(prev)
+	}
+}
+
+// WithExitFunc returns copy of log which calls given function instead of
+// function set by SetExitFunc in Fatal, Fatalf and Fatalln. Logs derived
+// from returned log inherit function. Returned log shares runtime level
+// with parent.
+func (l *Log) WithExitFunc(fn func(code int)) (res *Log) {
+	res = l.clone()
+	res.exit = fn
+	return res
+}
+
+/*
+Fatal logs value with CRITICAL severity level, flushes appender and
+calls function set by WithExitFunc or SetExitFunc (os.Exit by default)
+with code 1.
+
+Fatal and Panic families have no severity levels of their own: entries
+are written with LevelCritical and can't be distinguished from entries
+written by Critical by appenders and filters.
+*/
+func (l *Log) Fatal(v ...interface{}) {
+	if l.level.enabled(LevelCritical) {
+		l.append(LevelCritical, fmt.Sprint(v...))
+	}
+	l.Flush(context.Background())
+	l.exitFunc()(1)
+}
+
+// Fatalf logs formatted value with CRITICAL severity level, flushes
+// appender and exits with code 1. See Fatal.
+func (l *Log) Fatalf(format string, v ...interface{}) {
+	if l.level.enabled(LevelCritical) {
+		l.append(LevelCritical, fmt.Sprintf(format, v...))
+	}
+	l.Flush(context.Background())
+	l.exitFunc()(1)
+}
+
+// Fatalln logs value formatted like fmt.Sprintln with CRITICAL severity
+// level, flushes appender and exits with code 1. See Fatal.
+func (l *Log) Fatalln(v ...interface{}) {
+	if l.level.enabled(LevelCritical) {
+		l.append(LevelCritical, fmt.Sprintln(v...))
+	}
+	l.Flush(context.Background())
+	l.exitFunc()(1)
+}
+
+// Panic logs value with CRITICAL severity level, flushes appender and
+// panics with logged message.
+func (l *Log) Panic(v ...interface{}) {
+	s := fmt.Sprint(v...)
+	if l.level.enabled(LevelCritical) {
+		l.append(LevelCritical, s)
+	}
+	l.Flush(context.Background())
+	panic(s)
+}
+
+// Panicf logs formatted value with CRITICAL severity level, flushes
+// appender and panics with logged message.
+func (l *Log) Panicf(format string, v ...interface{}) {
+	s := fmt.Sprintf(format, v...)
+	if l.level.enabled(LevelCritical) {
+		l.append(LevelCritical, s)
+	}
+	l.Flush(context.Background())
+	panic(s)
+}
+
+// Panicln logs value formatted like fmt.Sprintln with CRITICAL severity
+// level, flushes appender and panics with logged message.
+func (l *Log) Panicln(v ...interface{}) {
+	s := fmt.Sprintln(v...)
+	if l.level.enabled(LevelCritical) {
+		l.append(LevelCritical, s)
+	}
+	l.Flush(context.Background())
+	panic(s)
+}
+
+// exitFunc returns function called by Fatal.
+func (l *Log) exitFunc() (fn func(code int)) {
+	if l.exit == nil {
+		return exitHook.Load().(func(code int))
+	}
+	return l.exit
+}
+
+// Flush writes entries buffered by appender if appender implements
+// Flusher.
+func (l *Log) Flush(ctx context.Context) (err error) {
+	if f, ok := l.appender.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package logx_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+type flushAppender struct {
+	*logx.TextAppender
+	flushed *int
+}
+
+func (a flushAppender) Clone(prefix string, tags []string) logx.Appender {
+	return flushAppender{
+		TextAppender: a.TextAppender.Clone(prefix, tags).(*logx.TextAppender),
+		flushed:      a.flushed,
+	}
+}
+
+func (a flushAppender) Flush(ctx context.Context) error {
+	*a.flushed++
+	return nil
+}
+
+func TestLog_Fatal(t *testing.T) {
+	var code int
+	var buf bytes.Buffer
+	var flushed int
+	l := logx.NewLog(flushAppender{
+		TextAppender: logx.NewTextAppender(&buf, 0),
+		flushed:      &flushed,
+	}, "parent").WithExitFunc(func(c int) {
+		code = c
+	}).GetLog("test")
+	l.Fatal("a")
+	l.Fatalf("f:%s", "b")
+	l.Fatalln("c")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, 3, flushed)
+	assert.Equal(t, "CRITICAL test a\nCRITICAL test f:b\nCRITICAL test c\n", buf.String())
+}
+
+func TestSetExitFunc(t *testing.T) {
+	var code, own int
+	restore := logx.SetExitFunc(func(c int) {
+		code = c
+	})
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, 0), "test")
+	l.Fatal("global")
+	l.WithExitFunc(func(c int) {
+		own = c
+	}).Fatal("own")
+	restore()
+	assert.Equal(t, 1, code)
+	assert.Equal(t, 1, own)
+	assert.Equal(t, "CRITICAL test global\nCRITICAL test own\n", buf.String())
+
+	// restored function is not called
+	code = 0
+	defer logx.SetExitFunc(func(int) {})()
+	l.Fatal("restored")
+	assert.Equal(t, 0, code)
+}
+
+func TestLog_Panic(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, 0), "test")
+	recovered := func(fn func()) (v interface{}) {
+		defer func() {
+			v = recover()
+		}()
+		fn()
+		return nil
+	}
+	assert.Equal(t, "a", recovered(func() {
+		l.Panic("a")
+	}))
+	assert.Equal(t, "f:b", recovered(func() {
+		l.Panicf("f:%s", "b")
+	}))
+	assert.Equal(t, "c d\n", recovered(func() {
+		l.Panicln("c", "d")
+	}))
+	assert.Equal(t, "CRITICAL test a\nCRITICAL test f:b\nCRITICAL test c d\n", buf.String())
+}
@@ -0,0 +1,299 @@
+//go:build linux
+// +build linux
+
+package logx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// JournalSocket is path of systemd-journald native protocol socket.
+const JournalSocket = "/run/systemd/journal/socket"
+
+var errJournalClosed = errors.New("logx: journal appender is closed")
+
+// journalReservedFields are fields set by JournalAppender. Entry fields
+// with the same names are sent with "FIELD_" prefix.
+var journalReservedFields = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"SYSLOG_IDENTIFIER": true,
+	"CODE_FILE":         true,
+	"CODE_LINE":         true,
+	"CODE_FUNC":         true,
+	"TAG":               true,
+	"STACK":             true,
+}
+
+/*
+JournalAppender writes entries to systemd-journald using native protocol.
+Each entry is sent with following fields:
+
+	PRIORITY            syslog severity of level
+	MESSAGE             log line
+	SYSLOG_IDENTIFIER   log prefix, omitted if empty
+	CODE_FILE           call site file
+	CODE_LINE           call site line
+	CODE_FUNC           call site function
+	TAG                 one field per log tag
+	STACK               captured stack, one frame per line
+
+Entry fields are sent as journal fields with names converted to upper
+case. Characters which are not allowed in journal field names are
+replaced with underscore. Fields with names listed above are sent with
+"FIELD_" prefix, so "message" field is sent as FIELD_MESSAGE.
+
+Entries which do not fit into single datagram are passed to journald
+through unlinked file in /dev/shm. JournalAppender and all its clones share the same
+socket. Entries appended after Close are reported as write errors.
+*/
+type JournalAppender struct {
+	*writeErrors
+	conn *journalConn
+
+	identity []byte
+}
+
+// NewJournalAppender returns appender which writes to journald socket with
+// given path. Empty path means JournalSocket.
+func NewJournalAppender(path string) (a *JournalAppender, err error) {
+	if path == "" {
+		path = JournalSocket
+	}
+	c := &journalConn{
+		addr: &net.UnixAddr{Name: path, Net: "unixgram"},
+	}
+	if err = c.connect(); err != nil {
+		return nil, err
+	}
+	return &JournalAppender{
+		writeErrors: &writeErrors{},
+		conn:        c,
+	}, nil
+}
+
+// Clone returns copy of JournalAppender with given prefix and tags
+func (a *JournalAppender) Clone(prefix string, tags []string) (a1 Appender) {
+	a2 := &JournalAppender{
+		writeErrors: a.writeErrors,
+		conn:        a.conn,
+	}
+	a2.setIdentity(prefix, tags)
+	return a2
+}
+
+// Append writes line with given level. Unknown levels are written as info.
+func (a *JournalAppender) Append(level, line string) {
+	l, _ := levelByName(level)
+	a.AppendEntry(&Entry{
+		Level: l,
+		Line:  line,
+		Time:  time.Now(),
+		PC:    callerPC(1),
+	})
+}
+
+// AppendEntry writes entry to journald.
+func (a *JournalAppender) AppendEntry(entry *Entry) {
+	a.TryAppendEntry(entry)
+}
+
+// TryAppendEntry writes entry to journald and returns write error.
+func (a *JournalAppender) TryAppendEntry(entry *Entry) (err error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	severity := 6
+	if entry.Level >= LevelTrace && entry.Level <= LevelCritical {
+		severity = syslogSeverities[entry.Level]
+	}
+	buf.WriteString("PRIORITY=")
+	itoaBuf(buf, severity, -1)
+	buf.WriteByte('\n')
+	writeJournalField(buf, "MESSAGE", trimNewline(entry.Line))
+	buf.Write(a.identity)
+	if entry.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{entry.PC}).Next()
+		if frame.File != "" {
+			writeJournalField(buf, "CODE_FILE", frame.File)
+			buf.WriteString("CODE_LINE=")
+			itoaBuf(buf, frame.Line, -1)
+			buf.WriteByte('\n')
+		}
+		if frame.Function != "" {
+			writeJournalField(buf, "CODE_FUNC", frame.Function)
+		}
+	}
+	for _, field := range entry.Fields {
+		writeJournalField(buf, journalFieldName(field.Key), rawValue(field.Value))
+	}
+	if len(entry.Stack) > 0 {
+		writeJournalField(buf, "STACK", strings.Join(stackFrames(entry.Stack), "\n"))
+	}
+	err = a.conn.write(buf.Bytes())
+	buf.Reset()
+	bufferPool.Put(buf)
+	return a.report(err)
+}
+
+// Close closes journald socket. Entries appended after Close are not
+// sent.
+func (a *JournalAppender) Close() (err error) {
+	return a.conn.close()
+}
+
+func (a *JournalAppender) setIdentity(prefix string, tags []string) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	if prefix != "" {
+		writeJournalField(buf, "SYSLOG_IDENTIFIER", prefix)
+	}
+	for _, tag := range tags {
+		writeJournalField(buf, "TAG", tag)
+	}
+	a.identity = make([]byte, buf.Len())
+	copy(a.identity, buf.Bytes())
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// writeJournalField writes field in native protocol. Values with newlines
+// are written in binary form with explicit length.
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if strings.IndexByte(value, '\n') < 0 {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	buf.WriteByte('\n')
+	buf.Write(size[:])
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalFieldName converts key to valid journal field name which
+// consists of upper case letters, digits and underscores and does not
+// start with underscore or digit.
+func journalFieldName(key string) (res string) {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || name[0] == '_' || (name[0] >= '0' && name[0] <= '9') {
+		name = append([]byte("F"), name...)
+	}
+	if journalReservedFields[string(name)] {
+		name = append([]byte("FIELD_"), name...)
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return string(name)
+}
+
+type journalConn struct {
+	addr *net.UnixAddr
+
+	mu     sync.Mutex
+	conn   *net.UnixConn
+	closed bool
+}
+
+func (c *journalConn) connect() (err error) {
+	c.conn, err = net.DialUnix("unixgram", nil, c.addr)
+	return err
+}
+
+// write sends datagram with one reconnect attempt on failure. Messages
+// which are too large for datagram are sent through file descriptor.
+func (c *journalConn) write(msg []byte) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errJournalClosed
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.conn == nil {
+			if err = c.connect(); err != nil {
+				continue
+			}
+		}
+		if _, err = c.conn.Write(msg); err == nil {
+			return nil
+		}
+		if isErrno(err, syscall.EMSGSIZE) || isErrno(err, syscall.ENOBUFS) {
+			return c.writeFd(msg)
+		}
+		c.conn.Close()
+		c.conn = nil
+	}
+	return err
+}
+
+// writeFd writes message to temporary file and passes its descriptor to
+// journald.
+func (c *journalConn) writeFd(msg []byte) (err error) {
+	f, err := journalTempFile()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err = f.Write(msg); err != nil {
+		return err
+	}
+	raw, err := c.conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	rights := syscall.UnixRights(int(f.Fd()))
+	if wErr := raw.Write(func(fd uintptr) (done bool) {
+		err = syscall.Sendmsg(int(fd), nil, rights, nil, 0)
+		return err != syscall.EAGAIN
+	}); wErr != nil {
+		return wErr
+	}
+	return err
+}
+
+func (c *journalConn) close() (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.conn != nil {
+		err = c.conn.Close()
+		c.conn = nil
+	}
+	return err
+}
+
+// journalTempFile returns unlinked file in /dev/shm.
+func journalTempFile() (f *os.File, err error) {
+	if f, err = ioutil.TempFile("/dev/shm", "logx-journal"); err != nil {
+		return nil, err
+	}
+	os.Remove(f.Name())
+	return f, nil
+}
+
+func isErrno(err error, errno syscall.Errno) (ok bool) {
+	if opErr, isOp := err.(*net.OpError); isOp {
+		err = opErr.Err
+	}
+	if sysErr, isSys := err.(*os.SyscallError); isSys {
+		err = sysErr.Err
+	}
+	return err == errno
+}
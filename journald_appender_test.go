@@ -0,0 +1,146 @@
+//go:build linux
+// +build linux
+
+package logx_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+// journalListener is fake journald socket.
+type journalListener struct {
+	*net.UnixConn
+	dir string
+}
+
+func newJournalListener(t *testing.T) (l *journalListener) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{
+		Name: filepath.Join(dir, "socket"),
+		Net:  "unixgram",
+	})
+	assert.NoError(t, err)
+	return &journalListener{UnixConn: conn, dir: dir}
+}
+
+func (l *journalListener) path() (res string) {
+	return filepath.Join(l.dir, "socket")
+}
+
+func (l *journalListener) close() {
+	l.Close()
+	os.RemoveAll(l.dir)
+}
+
+// read reads entry from datagram or passed file descriptor.
+func (l *journalListener) read(t *testing.T) (fields map[string][]string) {
+	buf := make([]byte, 64<<10)
+	oob := make([]byte, 1024)
+	n, oobn, _, _, err := l.ReadMsgUnix(buf, oob)
+	assert.NoError(t, err)
+	data := buf[:n]
+	if oobn > 0 {
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		assert.NoError(t, err)
+		fds, err := syscall.ParseUnixRights(&msgs[0])
+		assert.NoError(t, err)
+		f := os.NewFile(uintptr(fds[0]), "journal")
+		defer f.Close()
+		f.Seek(0, 0)
+		data, err = ioutil.ReadAll(f)
+		assert.NoError(t, err)
+	}
+	fields = map[string][]string{}
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		line := string(data[:i])
+		data = data[i+1:]
+		if eq := strings.IndexByte(line, '='); eq >= 0 {
+			fields[line[:eq]] = append(fields[line[:eq]], line[eq+1:])
+			continue
+		}
+		size := binary.LittleEndian.Uint64(data)
+		fields[line] = append(fields[line], string(data[8:8+size]))
+		data = data[8+size+1:]
+	}
+	return fields
+}
+
+func TestJournalAppender(t *testing.T) {
+	ln := newJournalListener(t)
+	defer ln.close()
+
+	a, err := logx.NewJournalAppender(ln.path())
+	assert.NoError(t, err)
+	defer a.Close()
+
+	l := logx.NewLog(a, "api", "a", "b")
+	l.With("user-id", 1).Error("multi\nline")
+	f := ln.read(t)
+	assert.Equal(t, []string{"3"}, f["PRIORITY"])
+	assert.Equal(t, []string{"multi\nline"}, f["MESSAGE"])
+	assert.Equal(t, []string{"api"}, f["SYSLOG_IDENTIFIER"])
+	assert.Equal(t, []string{"a", "b"}, f["TAG"])
+	assert.Equal(t, []string{"1"}, f["USER_ID"])
+	assert.Equal(t, "journald_appender_test.go", filepath.Base(f["CODE_FILE"][0]))
+	assert.Equal(t, []string{"90"}, f["CODE_LINE"])
+	assert.Equal(t, []string{"github.com/akaspin/logx_test.TestJournalAppender"}, f["CODE_FUNC"])
+
+	logx.NewLog(a, "").Notice("plain")
+	f = ln.read(t)
+	assert.Equal(t, []string{"5"}, f["PRIORITY"])
+	assert.Equal(t, []string{"plain"}, f["MESSAGE"])
+	assert.Nil(t, f["SYSLOG_IDENTIFIER"])
+	assert.Nil(t, f["TAG"])
+	assert.Equal(t, uint64(0), a.WriteFailures())
+
+	assert.NoError(t, a.Close())
+	assert.Error(t, a.TryAppendEntry(&logx.Entry{Level: logx.LevelNotice, Line: "closed"}))
+	assert.Equal(t, uint64(1), a.WriteFailures())
+}
+
+func TestJournalAppender_Large(t *testing.T) {
+	ln := newJournalListener(t)
+	defer ln.close()
+
+	a, err := logx.NewJournalAppender(ln.path())
+	assert.NoError(t, err)
+	defer a.Close()
+
+	msg := strings.Repeat("x", 1<<20)
+	logx.NewLog(a, "api").Warning(msg)
+	f := ln.read(t)
+	assert.Equal(t, []string{"4"}, f["PRIORITY"])
+	assert.Equal(t, []string{"api"}, f["SYSLOG_IDENTIFIER"])
+	assert.Equal(t, []string{msg}, f["MESSAGE"])
+}
+
+func TestJournalAppender_ReservedFields(t *testing.T) {
+	ln := newJournalListener(t)
+	defer ln.close()
+
+	a, err := logx.NewJournalAppender(ln.path())
+	assert.NoError(t, err)
+	defer a.Close()
+
+	logx.NewLog(a, "api").With("message", "fake").With("priority", 0).With("tag", "t").Notice("real")
+	f := ln.read(t)
+	assert.Equal(t, []string{"real"}, f["MESSAGE"])
+	assert.Equal(t, []string{"5"}, f["PRIORITY"])
+	assert.Nil(t, f["TAG"])
+	assert.Equal(t, []string{"fake"}, f["FIELD_MESSAGE"])
+	assert.Equal(t, []string{"0"}, f["FIELD_PRIORITY"])
+	assert.Equal(t, []string{"t"}, f["FIELD_TAG"])
+}
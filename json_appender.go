@@ -0,0 +1,290 @@
+package logx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const hex = "0123456789abcdef"
+
+/*
+JSONAppender writes each entry as single JSON object followed by newline.
+
+Format:
+
+	{"ts":"time","level":"LEVEL","prefix":"prefix","tags":["tag"],"caller":"file:line","msg":"message","key":"value"}
+
+"ts" and "caller" keys are written only if corresponding flags are set.
+"ts" is omitted for entries with zero time.
+Time is written in RFC 3339 format with nanoseconds regardless of which
+time flags are set. "prefix" and "tags" are omitted if empty. Fields
+follow "msg" key. Fields with keys reserved by appender ("ts", "level",
+"prefix", "tags", "caller", "msg" and "stack") are written with "fields."
+prefix. "stack" array of "function file:line" frames is written last if
+entry has captured stack.
+*/
+type JSONAppender struct {
+	output io.Writer
+	flags  int
+	*writeErrors
+
+	identity []byte
+}
+
+// NewJSONAppender returns new JSON appender without prefix and tags
+func NewJSONAppender(output io.Writer, flags int) (a *JSONAppender) {
+	a = &JSONAppender{
+		output:      syncOutput(output, flags),
+		flags:       flags,
+		writeErrors: &writeErrors{},
+	}
+	return a
+}
+
+// Clone returns copy of JSONAppender with given prefix and tags
+func (a *JSONAppender) Clone(prefix string, tags []string) (a1 Appender) {
+	a1 = &JSONAppender{
+		output:      a.output,
+		flags:       a.flags,
+		writeErrors: a.writeErrors,
+	}
+	a1.(*JSONAppender).setIdentity(prefix, tags)
+	return a1
+}
+
+// Append writes log line with given level.
+func (a *JSONAppender) Append(level, line string) {
+	a.write(level, &Entry{
+		Line: line,
+		Time: time.Now(),
+		PC:   callerPC(1),
+	})
+}
+
+// AppendEntry writes log entry with fields as additional properties.
+func (a *JSONAppender) AppendEntry(entry *Entry) {
+	a.write(entry.Level.String(), entry)
+}
+
+// TryAppendEntry writes log entry and returns write error.
+func (a *JSONAppender) TryAppendEntry(entry *Entry) (err error) {
+	return a.write(entry.Level.String(), entry)
+}
+
+func (a *JSONAppender) write(level string, entry *Entry) (err error) {
+	line := entry.Line
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.WriteByte('{')
+
+	// time
+	if a.flags&(Ldate|Ltime|Lmicroseconds) != 0 && !entry.Time.IsZero() {
+		var scratch [64]byte
+		ts := entry.Time
+		if a.flags&LUTC != 0 {
+			ts = ts.UTC()
+		}
+		buf.WriteString(`"ts":"`)
+		buf.Write(ts.AppendFormat(scratch[:0], time.RFC3339Nano))
+		buf.WriteString(`",`)
+	}
+
+	// level
+	buf.WriteString(`"level":`)
+	writeJSONString(buf, level)
+
+	// identity
+	buf.Write(a.identity)
+
+	// file
+	if a.flags&(Lshortfile|Llongfile) != 0 {
+		buf.WriteString(`,"caller":`)
+		caller := bufferPool.Get().(*bytes.Buffer)
+		writeCaller(caller, a.flags, entry.PC)
+		writeJSONString(buf, caller.String())
+		caller.Reset()
+		bufferPool.Put(caller)
+	}
+
+	// message
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		line = line[:len(line)-1]
+	}
+	buf.WriteString(`,"msg":`)
+	if a.flags&Lcompact != 0 {
+		msg := bufferPool.Get().(*bytes.Buffer)
+		stripBuf(msg, line)
+		writeJSONString(buf, msg.String())
+		msg.Reset()
+		bufferPool.Put(msg)
+	} else {
+		writeJSONString(buf, line)
+	}
+
+	// fields
+	for _, field := range entry.Fields {
+		buf.WriteByte(',')
+		if isReservedKey(field.Key) {
+			writeJSONString(buf, reservedKeyPrefix+field.Key)
+		} else {
+			writeJSONString(buf, field.Key)
+		}
+		buf.WriteByte(':')
+		writeJSONValue(buf, field.Value)
+	}
+
+	// stack
+	if len(entry.Stack) > 0 {
+		buf.WriteString(`,"stack":[`)
+		for i, frame := range stackFrames(entry.Stack) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, frame)
+		}
+		buf.WriteByte(']')
+	}
+
+	buf.WriteString("}\n")
+	_, err = buf.WriteTo(a.output)
+	buf.Reset()
+	bufferPool.Put(buf)
+	return a.report(err)
+}
+
+func (a *JSONAppender) setIdentity(prefix string, tags []string) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	if prefix != "" {
+		buf.WriteString(`,"prefix":`)
+		writeJSONString(buf, prefix)
+	}
+	if len(tags) > 0 {
+		buf.WriteString(`,"tags":[`)
+		for i, tag := range tags {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(buf, tag)
+		}
+		buf.WriteByte(']')
+	}
+	a.identity = make([]byte, buf.Len())
+	copy(a.identity, buf.Bytes())
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// writeJSONValue writes value as JSON. Common types are written without
+// reflection. Other values are encoded with encoding/json and fall back to
+// string representation if encoding fails.
+func writeJSONValue(buf *bytes.Buffer, value interface{}) {
+	var scratch [64]byte
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		writeJSONString(buf, v)
+	case bool:
+		buf.Write(strconv.AppendBool(scratch[:0], v))
+	case int:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int8:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int16:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int32:
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int64:
+		buf.Write(strconv.AppendInt(scratch[:0], v, 10))
+	case uint:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint8:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint16:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint32:
+		buf.Write(strconv.AppendUint(scratch[:0], uint64(v), 10))
+	case uint64:
+		buf.Write(strconv.AppendUint(scratch[:0], v, 10))
+	case float32:
+		writeJSONFloat(buf, float64(v), 32)
+	case float64:
+		writeJSONFloat(buf, v, 64)
+	case json.Marshaler:
+		writeJSONMarshal(buf, v)
+	case error:
+		writeJSONString(buf, v.Error())
+	case fmt.Stringer:
+		writeJSONString(buf, v.String())
+	default:
+		writeJSONMarshal(buf, v)
+	}
+}
+
+func writeJSONFloat(buf *bytes.Buffer, f float64, bits int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		writeJSONString(buf, strconv.FormatFloat(f, 'g', -1, bits))
+		return
+	}
+	var scratch [64]byte
+	buf.Write(strconv.AppendFloat(scratch[:0], f, 'g', -1, bits))
+}
+
+func writeJSONMarshal(buf *bytes.Buffer, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		writeJSONString(buf, err.Error())
+		return
+	}
+	buf.Write(data)
+}
+
+// writeJSONString writes quoted and escaped JSON string. Invalid UTF-8
+// sequences are replaced with U+FFFD.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			buf.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(c)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case '\t':
+				buf.WriteString(`\t`)
+			default:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[c>>4])
+				buf.WriteByte(hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.WriteString(s[start:i])
+			buf.WriteString("\ufffd")
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
@@ -0,0 +1,65 @@
+package logx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONAppender(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewJSONAppender(&buf, logx.Lshortfile), "test", "a", "b")
+	l.With("n", 42).With("err", errors.New("bang")).Notice("multi\n\"line\"\x01")
+	l.GetLog("").Error("plain")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var v map[string]interface{}
+	assert.NoError(t, json.Unmarshal(lines[0], &v))
+	assert.Equal(t, map[string]interface{}{
+		"level":  "NOTICE",
+		"prefix": "test",
+		"tags":   []interface{}{"a", "b"},
+		"caller": "json_appender_test.go:18",
+		"msg":    "multi\n\"line\"\x01",
+		"n":      float64(42),
+		"err":    "bang",
+	}, v)
+	assert.Equal(t, `{"level":"ERROR","caller":"json_appender_test.go:19","msg":"plain"}`, string(lines[1]))
+}
+
+func TestJSONAppender_Time(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewJSONAppender(&buf, logx.Ldate|logx.Ltime|logx.LUTC), "")
+	l.Notice("test")
+
+	var v map[string]string
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &v))
+	ts, err := time.Parse(time.RFC3339Nano, v["ts"])
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), ts, time.Minute)
+	assert.Regexp(t, `Z$`, v["ts"])
+}
+
+func TestJSONAppender_ReservedKeys(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewJSONAppender(&buf, 0), "test")
+	l.With("level", "fake").With("msg", "fake").With("ts", 1).With("user", "ok").Notice("real")
+	assert.Equal(t, `{"level":"NOTICE","prefix":"test","msg":"real","fields.level":"fake","fields.msg":"fake","fields.ts":1,"user":"ok"}`+"\n", buf.String())
+}
+
+func BenchmarkJSONAppender(b *testing.B) {
+	l := logx.NewLog(logx.NewJSONAppender(ioutil.Discard, logx.LstdFlags), "test", "a")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.With("n", i).Notice("benchmark message")
+	}
+}
@@ -0,0 +1,131 @@
+package logx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Level is severity level of log entry. Levels are ordered from
+// the most chatty TRACE to the most severe CRITICAL.
+type Level int32
+
+const (
+	// LevelTrace is TRACE severity level.
+	LevelTrace Level = iota
+
+	// LevelDebug is DEBUG severity level.
+	LevelDebug
+
+	// LevelInfo is INFO severity level.
+	LevelInfo
+
+	// LevelNotice is NOTICE severity level.
+	LevelNotice
+
+	// LevelWarning is WARNING severity level.
+	LevelWarning
+
+	// LevelError is ERROR severity level.
+	LevelError
+
+	// LevelCritical is CRITICAL severity level.
+	LevelCritical
+)
+
+const (
+	lTrace    = "TRACE"
+	lDebug    = "DEBUG"
+	lInfo     = "INFO"
+	lNotice   = "NOTICE"
+	lWarning  = "WARNING"
+	lError    = "ERROR"
+	lCritical = "CRITICAL"
+)
+
+var levelNames = [...]string{
+	LevelTrace:    lTrace,
+	LevelDebug:    lDebug,
+	LevelInfo:     lInfo,
+	LevelNotice:   lNotice,
+	LevelWarning:  lWarning,
+	LevelError:    lError,
+	LevelCritical: lCritical,
+}
+
+// String returns name of level. Names of unknown levels are formatted
+// as "Level(n)".
+func (l Level) String() (res string) {
+	if l < LevelTrace || l > LevelCritical {
+		return "Level(" + strconv.Itoa(int(l)) + ")"
+	}
+	return levelNames[l]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Level) MarshalText() (text []byte, err error) {
+	if l < LevelTrace || l > LevelCritical {
+		return nil, fmt.Errorf("logx: unknown level %d", int(l))
+	}
+	return []byte(levelNames[l]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Level names are
+// case-insensitive.
+func (l *Level) UnmarshalText(text []byte) (err error) {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// ParseLevel returns level with given case-insensitive name. "WARN" and
+// "CRIT" are accepted as synonyms of WARNING and CRITICAL. Unknown names
+// are rejected with error.
+func ParseLevel(name string) (level Level, err error) {
+	if level, ok := levelByName(strings.ToUpper(name)); ok {
+		return level, nil
+	}
+	return LevelInfo, fmt.Errorf("logx: unknown level %q", name)
+}
+
+// levelByName returns level with given exact name.
+func levelByName(name string) (level Level, ok bool) {
+	for i, n := range levelNames {
+		if n == name {
+			return Level(i), true
+		}
+	}
+	switch name {
+	case "WARN":
+		return LevelWarning, true
+	case "CRIT":
+		return LevelCritical, true
+	}
+	return LevelInfo, false
+}
+
+// levelVar holds runtime level threshold shared between log
+// and all derived logs.
+type levelVar struct {
+	v int32
+}
+
+func newLevelVar(level Level) (res *levelVar) {
+	return &levelVar{v: int32(level)}
+}
+
+func (v *levelVar) get() (res Level) {
+	return Level(atomic.LoadInt32(&v.v))
+}
+
+func (v *levelVar) set(level Level) {
+	atomic.StoreInt32(&v.v, int32(level))
+}
+
+func (v *levelVar) enabled(level Level) (ok bool) {
+	return level >= v.get()
+}
@@ -0,0 +1,93 @@
+package logx
+
+import (
+	"context"
+)
+
+// LevelFilter is appender which drops entries below threshold level
+// before sending them to wrapped appender. Levels are compared by
+// severity. Threshold is shared between filter and all its clones.
+type LevelFilter struct {
+	appender Appender
+	level    *levelVar
+}
+
+// NewLevelFilter returns appender which sends entries with given level
+// and above to given appender.
+func NewLevelFilter(appender Appender, level Level) (a *LevelFilter) {
+	return &LevelFilter{
+		appender: appender,
+		level:    newLevelVar(level),
+	}
+}
+
+// Clone returns LevelFilter which wraps clone of appender with given
+// prefix and tags.
+func (a *LevelFilter) Clone(prefix string, tags []string) (a1 Appender) {
+	return &LevelFilter{
+		appender: a.appender.Clone(prefix, tags),
+		level:    a.level,
+	}
+}
+
+// Append sends line to wrapped appender if level passes threshold.
+// Lines with unknown levels are always sent.
+func (a *LevelFilter) Append(level, line string) {
+	if l, ok := levelByName(level); ok && !a.level.enabled(l) {
+		return
+	}
+	a.appender.Append(level, line)
+}
+
+// AppendEntry sends entry to wrapped appender if entry level passes
+// threshold.
+func (a *LevelFilter) AppendEntry(entry *Entry) {
+	if !a.level.enabled(entry.Level) {
+		return
+	}
+	appendEntry(a.appender, entry)
+}
+
+// TryAppendEntry sends entry to wrapped appender if entry level passes
+// threshold and returns write error of wrapped CheckedAppender.
+func (a *LevelFilter) TryAppendEntry(entry *Entry) (err error) {
+	if !a.level.enabled(entry.Level) {
+		return nil
+	}
+	return tryAppendEntry(a.appender, entry)
+}
+
+// SetErrorHandler sets error handler of wrapped appender if it
+// implements CheckedAppender.
+func (a *LevelFilter) SetErrorHandler(handler ErrorHandler) {
+	if c, ok := a.appender.(CheckedAppender); ok {
+		c.SetErrorHandler(handler)
+	}
+}
+
+// WriteFailures returns number of failed writes of wrapped appender if it
+// implements CheckedAppender.
+func (a *LevelFilter) WriteFailures() (n uint64) {
+	if c, ok := a.appender.(CheckedAppender); ok {
+		return c.WriteFailures()
+	}
+	return 0
+}
+
+// Level returns threshold level.
+func (a *LevelFilter) Level() (res Level) {
+	return a.level.get()
+}
+
+// SetLevel atomically sets threshold level of filter and all its clones.
+func (a *LevelFilter) SetLevel(level Level) {
+	a.level.set(level)
+}
+
+// Flush flushes wrapped appender if it implements Flusher.
+func (a *LevelFilter) Flush(ctx context.Context) (err error) {
+	if f, ok := a.appender.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package logx_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelFilter(t *testing.T) {
+	var all, warnings, critical bytes.Buffer
+	l := logx.NewLog(logx.NewMultiAppender(
+		logx.NewTextAppender(&all, 0),
+		logx.NewLevelFilter(logx.NewTextAppender(&warnings, 0), logx.LevelWarning),
+		logx.NewLevelFilter(logx.NewTextAppender(&critical, 0), logx.LevelCritical),
+	), "test")
+	l.Notice("notice")
+	l.Warning("warning")
+	l.Error("error")
+	l.Critical("critical")
+
+	assert.Equal(t, "NOTICE test notice\nWARNING test warning\nERROR test error\nCRITICAL test critical\n", all.String())
+	assert.Equal(t, "WARNING test warning\nERROR test error\nCRITICAL test critical\n", warnings.String())
+	assert.Equal(t, "CRITICAL test critical\n", critical.String())
+}
+
+func TestLevelFilter_Legacy(t *testing.T) {
+	var lines []string
+	f := logx.NewLevelFilter(lineAppender{lines: &lines}, logx.LevelError)
+	f.Append("WARNING", "skip")
+	f.Append("CRITICAL", "ok")
+	f.Append("CUSTOM", "unknown")
+	f.SetLevel(logx.LevelWarning)
+	f.Clone("", nil).Append("WARNING", "ok")
+	assert.Equal(t, []string{"CRITICAL ok", "CUSTOM unknown", "WARNING ok"}, lines)
+}
@@ -0,0 +1,49 @@
+package logx_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel(t *testing.T) {
+	for name, expect := range map[string]logx.Level{
+		"trace":    logx.LevelTrace,
+		"DEBUG":    logx.LevelDebug,
+		"Info":     logx.LevelInfo,
+		"notice":   logx.LevelNotice,
+		"warn":     logx.LevelWarning,
+		"WARNING":  logx.LevelWarning,
+		"error":    logx.LevelError,
+		"critical": logx.LevelCritical,
+	} {
+		level, err := logx.ParseLevel(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, expect, level, name)
+	}
+	_, err := logx.ParseLevel("warnign")
+	assert.Error(t, err)
+}
+
+func TestLevel_String(t *testing.T) {
+	assert.Equal(t, "NOTICE", logx.LevelNotice.String())
+	assert.Equal(t, "Level(42)", logx.Level(42).String())
+	assert.True(t, logx.LevelError > logx.LevelWarning)
+}
+
+func TestLevel_MarshalText(t *testing.T) {
+	var v struct {
+		Level logx.Level `json:"level"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"level":"error"}`), &v))
+	assert.Equal(t, logx.LevelError, v.Level)
+
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"level":"ERROR"}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"level":"eror"}`), &v))
+	assert.Equal(t, logx.LevelError, v.Level)
+}
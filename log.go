@@ -2,38 +2,83 @@ package logx
 
 import (
 	"fmt"
+	"time"
 )
 
-const (
-	lNotice   = "NOTICE"
-	lWarning  = "WARNING"
-	lError    = "ERROR"
-	lCritical = "CRITICAL"
-)
+// DefaultPrefixSeparator separates prefixes of parent and child logs
+// created by Sub unless log has other separator set by
+// WithPrefixSeparator.
+const DefaultPrefixSeparator = "."
 
 type Log struct {
 	prefix string
 	tags   []string
+	fields []Field
 
-	appender Appender
+	appender   Appender
+	level      *levelVar
+	callerSkip int
+	stackLevel Level
+	errStack   []uintptr
+	separator  string
+	exit       func(code int)
 }
 
-// Create new log
+// stackOff is stack level which disables stack capture.
+const stackOff = LevelCritical + 1
+
+// Create new log. Runtime level of new log is LevelTrace which means
+// that all levels enabled by build tags are emitted.
 func NewLog(appender Appender, prefix string, tags ...string) (res *Log) {
 	return &Log{
-		tags:     tags,
-		prefix:   prefix,
-		appender: appender.Clone(prefix, tags),
+		tags:       tags,
+		prefix:     prefix,
+		appender:   appender.Clone(prefix, tags),
+		level:      newLevelVar(LevelTrace),
+		stackLevel: stackOff,
+		separator:  DefaultPrefixSeparator,
 	}
 }
 
-// NewTextAppender log with given prefix and tags.
+// NewTextAppender log with given prefix and tags. Returned log shares
+// runtime level with parent.
 func (l *Log) GetLog(prefix string, tags ...string) (res *Log) {
-	return &Log{
-		prefix:   prefix,
-		tags:     tags,
-		appender: l.appender.Clone(prefix, tags),
+	res = l.clone()
+	res.prefix = prefix
+	res.tags = tags
+	res.appender = l.appender.Clone(prefix, tags)
+	return res
+}
+
+// Sub returns child log with prefix nested into prefix of parent and tags
+// added to tags of parent. Prefixes are joined with separator of log,
+// DefaultPrefixSeparator by default:
+//
+//	logx.GetLog("api").Sub("users", "v2") // prefix "api.users"
+//
+// Returned log shares runtime level with parent.
+func (l *Log) Sub(prefix string, tags ...string) (res *Log) {
+	switch {
+	case l.prefix == "":
+	case prefix == "":
+		prefix = l.prefix
+	default:
+		prefix = l.prefix + l.separator + prefix
 	}
+	res = l.clone()
+	res.prefix = prefix
+	res.tags = joinTags(l.tags, tags)
+	res.appender = l.appender.Clone(prefix, res.tags)
+	return res
+}
+
+// WithPrefixSeparator returns copy of log which joins prefixes in Sub
+// with given separator. Logs derived from returned log inherit separator.
+// Returned log shares runtime level with parent.
+func (l *Log) WithPrefixSeparator(separator string) (res *Log) {
+	res = l.clone()
+	res.separator = separator
+	return res
 }
 
 // Log prefix.
@@ -46,47 +91,195 @@ func (l *Log) Tags() (res []string) {
 	return l.tags
 }
 
-// NewTextAppender log instance wit given tags
+// Log fields.
+func (l *Log) Fields() (res []Field) {
+	return l.fields
+}
+
+// NewTextAppender log instance wit given tags. Returned log shares
+// runtime level with parent.
 func (l *Log) WithTags(tags ...string) (res *Log) {
-	return NewLog(l.appender, l.prefix, tags...)
+	res = l.clone()
+	res.tags = tags
+	res.appender = l.appender.Clone(l.prefix, tags)
+	return res
+}
+
+// AddTags returns copy of log with given tags added to tags of parent.
+// Returned log shares runtime level with parent.
+func (l *Log) AddTags(tags ...string) (res *Log) {
+	res = l.clone()
+	res.tags = joinTags(l.tags, tags)
+	res.appender = l.appender.Clone(l.prefix, res.tags)
+	return res
+}
+
+// With returns copy of log with given key/value field added to all
+// entries. Returned log shares runtime level with parent.
+func (l *Log) With(key string, value interface{}) (res *Log) {
+	return l.WithFields(Field{Key: key, Value: value})
+}
+
+// WithFields returns copy of log with given fields added to all entries.
+// Returned log shares runtime level with parent.
+func (l *Log) WithFields(fields ...Field) (res *Log) {
+	res = l.clone()
+	res.fields = make([]Field, 0, len(l.fields)+len(fields))
+	res.fields = append(res.fields, l.fields...)
+	res.fields = append(res.fields, fields...)
+	return res
+}
+
+// WithLevel returns copy of log with independent runtime level.
+func (l *Log) WithLevel(level Level) (res *Log) {
+	res = l.clone()
+	res.level = newLevelVar(level)
+	return res
+}
+
+// WithCallerSkip returns copy of log which skips given number of
+// additional stack frames when reporting call site. Use it in wrappers
+// around Log. Skip is added to skip of parent.
+func (l *Log) WithCallerSkip(skip int) (res *Log) {
+	res = l.clone()
+	res.callerSkip += skip
+	return res
+}
+
+// WithStack returns copy of log which captures call stack of entries
+// with given or higher level. Stack starts from call site and excludes
+// frames of logx. TextAppender writes stack as indented lines after
+// entry, structured appenders write it as "stack" array.
+func (l *Log) WithStack(level Level) (res *Log) {
+	res = l.clone()
+	res.stackLevel = level
+	return res
+}
+
+// Level returns runtime level of log.
+func (l *Log) Level() (res Level) {
+	return l.level.get()
+}
+
+// SetLevel atomically sets runtime level of log and all logs derived
+// from it by GetLog and WithTags. Entries below runtime level are not
+// emitted. Levels omitted by build tags are never emitted regardless
+// of runtime level.
+func (l *Log) SetLevel(level Level) {
+	l.level.set(level)
+}
+
+// Enabled reports whether given level passes runtime level of log.
+func (l *Log) Enabled(level Level) (ok bool) {
+	return l.level.enabled(level)
 }
 
 // Notice logs value with NOTICE severity level.
 func (l *Log) Notice(v ...interface{}) {
-	l.appender.Append(lNotice, fmt.Sprint(v...))
+	if !l.level.enabled(LevelNotice) {
+		return
+	}
+	l.append(LevelNotice, fmt.Sprint(v...))
 }
 
 // Noticef logs formatted value with NOTICE severity level.
 func (l *Log) Noticef(format string, v ...interface{}) {
-	l.appender.Append(lNotice, fmt.Sprintf(format, v...))
+	if !l.level.enabled(LevelNotice) {
+		return
+	}
+	l.append(LevelNotice, fmt.Sprintf(format, v...))
 }
 
 // Warning logs value with WARNING severity level.
 func (l *Log) Warning(v ...interface{}) {
-	l.appender.Append(lWarning, fmt.Sprint(v...))
+	if !l.level.enabled(LevelWarning) {
+		return
+	}
+	l.append(LevelWarning, fmt.Sprint(v...))
 }
 
 // Warningf logs formatted value with WARNING severity level.
 func (l *Log) Warningf(format string, v ...interface{}) {
-	l.appender.Append(lWarning, fmt.Sprintf(format, v...))
+	if !l.level.enabled(LevelWarning) {
+		return
+	}
+	l.append(LevelWarning, fmt.Sprintf(format, v...))
 }
 
 // Error logs value with ERROR severity level.
 func (l *Log) Error(v ...interface{}) {
-	l.appender.Append(lError, fmt.Sprint(v...))
+	if !l.level.enabled(LevelError) {
+		return
+	}
+	l.append(LevelError, fmt.Sprint(v...))
 }
 
 // Errorf logs formatted value with ERROR severity level.
 func (l *Log) Errorf(format string, v ...interface{}) {
-	l.appender.Append(lError, fmt.Sprintf(format, v...))
+	if !l.level.enabled(LevelError) {
+		return
+	}
+	l.append(LevelError, fmt.Sprintf(format, v...))
 }
 
 // Critical logs value with CRITICAL severity level.
 func (l *Log) Critical(v ...interface{}) {
-	l.appender.Append(lCritical, fmt.Sprint(v...))
+	if !l.level.enabled(LevelCritical) {
+		return
+	}
+	l.append(LevelCritical, fmt.Sprint(v...))
 }
 
 // Criticalf logs formatted value with CRITICAL severity level.
 func (l *Log) Criticalf(format string, v ...interface{}) {
-	l.appender.Append(lCritical, fmt.Sprintf(format, v...))
+	if !l.level.enabled(LevelCritical) {
+		return
+	}
+	l.append(LevelCritical, fmt.Sprintf(format, v...))
+}
+
+// append sends line to appender with call site of caller of logging
+// method.
+func (l *Log) append(level Level, line string) {
+	l.appendPC(level, line, callerPC(2+l.callerSkip))
+}
+
+// appendPC sends line to appender with given call site.
+func (l *Log) appendPC(level Level, line string, pc uintptr) {
+	entry := &Entry{
+		Level:  level,
+		Line:   line,
+		Fields: l.fields,
+		Time:   time.Now(),
+		PC:     pc,
+	}
+	entry.Stack = l.entryStack(level, pc)
+	appendEntry(l.appender, entry)
+}
+
+// entryStack returns stack carried by error passed to WithError or call
+// stack from given call site if level passes stack level.
+func (l *Log) entryStack(level Level, pc uintptr) (stack []uintptr) {
+	if l.errStack != nil {
+		return l.errStack
+	}
+	if level >= l.stackLevel {
+		return captureStack(pc)
+	}
+	return nil
+}
+
+// joinTags returns new slice with parent tags followed by given tags.
+func joinTags(parent, tags []string) (res []string) {
+	if len(tags) == 0 {
+		return parent
+	}
+	res = make([]string, 0, len(parent)+len(tags))
+	res = append(res, parent...)
+	return append(res, tags...)
+}
+
+func (l *Log) clone() (res *Log) {
+	c := *l
+	return &c
 }
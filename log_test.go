@@ -46,3 +46,72 @@ func TestLog_Lshortfile(t *testing.T) {
 	l.Notice("lineno")
 	assert.Contains(t, buf.String(), "log_test.go:46")
 }
+
+func TestLog_SetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, 0), "test")
+	child := l.GetLog("child").WithTags("a")
+	assert.Equal(t, logx.LevelTrace, child.Level())
+
+	l.SetLevel(logx.LevelWarning)
+	assert.Equal(t, logx.LevelWarning, child.Level())
+	child.Notice("skip")
+	child.Warning("ok")
+	assert.Equal(t, "WARNING child [a] ok\n", buf.String())
+
+	buf.Reset()
+	independent := child.WithLevel(logx.LevelError)
+	l.SetLevel(logx.LevelTrace)
+	independent.Warning("skip")
+	child.Notice("ok")
+	assert.Equal(t, "NOTICE child [a] ok\n", buf.String())
+}
+
+func TestLog_With(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, 0), "test")
+	l.With("user_id", 42).With("name", "John Doe").Notice("login")
+	l.Notice("plain")
+	assert.Equal(t, "NOTICE test login user_id=42 name=\"John Doe\"\nNOTICE test plain\n", buf.String())
+}
+
+func TestLog_Sub(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, 0), "api", "a")
+	users := l.Sub("users", "b")
+	users.Notice("one")
+	users.Sub("").Notice("two")
+	logx.NewLog(logx.NewTextAppender(&buf, 0), "").Sub("root").Notice("three")
+	l.AddTags("c").AddTags("d").Notice("four")
+	l.Notice("five")
+	assert.Equal(t, []string{"a", "b"}, users.Tags())
+	assert.Equal(t, "NOTICE api.users [a b] one\n"+
+		"NOTICE api.users [a b] two\n"+
+		"NOTICE root three\n"+
+		"NOTICE api [a c d] four\n"+
+		"NOTICE api [a] five\n", buf.String())
+
+	buf.Reset()
+	l.WithPrefixSeparator("/").Sub("v1").Sub("users").Notice("six")
+	l.Sub("v1").Notice("seven")
+	assert.Equal(t, "NOTICE api/v1/users [a] six\nNOTICE api.v1 [a] seven\n", buf.String())
+}
+
+type lineAppender struct {
+	lines *[]string
+}
+
+func (a lineAppender) Append(level, line string) {
+	*a.lines = append(*a.lines, level+" "+line)
+}
+
+func (a lineAppender) Clone(prefix string, tags []string) logx.Appender {
+	return a
+}
+
+func TestLog_With_LegacyAppender(t *testing.T) {
+	var lines []string
+	l := logx.NewLog(lineAppender{lines: &lines}, "test")
+	l.WithFields(logx.Field{Key: "ok", Value: true}).Warning("message")
+	assert.Equal(t, []string{"WARNING message ok=true"}, lines)
+}
@@ -0,0 +1,157 @@
+package logx
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"time"
+)
+
+/*
+LogfmtAppender writes entries in logfmt format.
+
+Format:
+
+	ts="time" level=level prefix=prefix tags="tags" caller=file:line msg=message key=value
+
+"ts" and "caller" keys are written only if corresponding flags are set.
+"ts" is omitted for entries with zero time.
+"prefix" and "tags" are omitted if empty. Values containing whitespace,
+quotes, equal signs or control characters are quoted. Such characters in
+field keys are replaced with "_". Fields with keys reserved by appender
+("ts", "level", "prefix", "tags", "caller", "msg" and "stack") are
+written with "fields." prefix.
+*/
+type LogfmtAppender struct {
+	output io.Writer
+	flags  int
+	*writeErrors
+
+	identity []byte
+}
+
+// NewLogfmtAppender returns new logfmt appender without prefix and tags
+func NewLogfmtAppender(output io.Writer, flags int) (a *LogfmtAppender) {
+	a = &LogfmtAppender{
+		output:      syncOutput(output, flags),
+		flags:       flags,
+		writeErrors: &writeErrors{},
+	}
+	return a
+}
+
+// Clone returns copy of LogfmtAppender with given prefix and tags
+func (a *LogfmtAppender) Clone(prefix string, tags []string) (a1 Appender) {
+	a1 = &LogfmtAppender{
+		output:      a.output,
+		flags:       a.flags,
+		writeErrors: a.writeErrors,
+	}
+	a1.(*LogfmtAppender).setIdentity(prefix, tags)
+	return a1
+}
+
+// Append writes log line with given level.
+func (a *LogfmtAppender) Append(level, line string) {
+	a.write(level, &Entry{
+		Line: line,
+		Time: time.Now(),
+		PC:   callerPC(1),
+	})
+}
+
+// AppendEntry writes log entry with fields after message.
+func (a *LogfmtAppender) AppendEntry(entry *Entry) {
+	a.write(entry.Level.String(), entry)
+}
+
+// TryAppendEntry writes log entry and returns write error.
+func (a *LogfmtAppender) TryAppendEntry(entry *Entry) (err error) {
+	return a.write(entry.Level.String(), entry)
+}
+
+func (a *LogfmtAppender) write(level string, entry *Entry) (err error) {
+	line := entry.Line
+	buf := bufferPool.Get().(*bytes.Buffer)
+
+	// time
+	if a.flags&(Ldate|Ltime|Lmicroseconds) != 0 && !entry.Time.IsZero() {
+		quote := a.flags&Ldate != 0 && a.flags&(Ltime|Lmicroseconds) != 0
+		buf.WriteString("ts=")
+		if quote {
+			buf.WriteByte('"')
+		}
+		writeTime(buf, entry.Time, a.flags)
+		if quote {
+			buf.WriteByte('"')
+		}
+		buf.WriteByte(' ')
+	}
+
+	// level
+	buf.WriteString("level=")
+	writeString(buf, strings.ToLower(level))
+
+	// identity
+	buf.Write(a.identity)
+
+	// file
+	if a.flags&(Lshortfile|Llongfile) != 0 {
+		buf.WriteString(" caller=")
+		caller := bufferPool.Get().(*bytes.Buffer)
+		writeCaller(caller, a.flags, entry.PC)
+		writeString(buf, caller.String())
+		caller.Reset()
+		bufferPool.Put(caller)
+	}
+
+	// message
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		line = line[:len(line)-1]
+	}
+	buf.WriteString(" msg=")
+	if a.flags&Lcompact != 0 {
+		msg := bufferPool.Get().(*bytes.Buffer)
+		stripBuf(msg, line)
+		writeString(buf, msg.String())
+		msg.Reset()
+		bufferPool.Put(msg)
+	} else {
+		writeString(buf, line)
+	}
+
+	for _, field := range entry.Fields {
+		buf.WriteByte(' ')
+		if isReservedKey(field.Key) {
+			buf.WriteString(reservedKeyPrefix)
+		}
+		writeKey(buf, field.Key)
+		buf.WriteByte('=')
+		writeValue(buf, field.Value)
+	}
+	if len(entry.Stack) > 0 {
+		buf.WriteString(" stack=")
+		writeString(buf, strings.Join(stackFrames(entry.Stack), "\n"))
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(a.output)
+	buf.Reset()
+	bufferPool.Put(buf)
+	return a.report(err)
+}
+
+func (a *LogfmtAppender) setIdentity(prefix string, tags []string) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	if prefix != "" {
+		buf.WriteString(" prefix=")
+		writeString(buf, prefix)
+	}
+	if len(tags) > 0 {
+		buf.WriteString(" tags=")
+		writeString(buf, strings.Join(tags, " "))
+	}
+	a.identity = make([]byte, buf.Len())
+	copy(a.identity, buf.Bytes())
+	buf.Reset()
+	bufferPool.Put(buf)
+}
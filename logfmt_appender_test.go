@@ -0,0 +1,48 @@
+package logx_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogfmtAppender(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewLogfmtAppender(&buf, logx.Lshortfile), "api", "a", "b")
+	l.With("user", "John \"JD\" Doe").Notice("multi\nline")
+	l.GetLog("").Warning("plain")
+	assert.Equal(t, ""+
+		`level=notice prefix=api tags="a b" caller=logfmt_appender_test.go:14 msg="multi\nline" user="John \"JD\" Doe"`+"\n"+
+		`level=warning caller=logfmt_appender_test.go:15 msg=plain`+"\n",
+		buf.String())
+}
+
+func TestLogfmtAppender_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewLogfmtAppender(&buf, 0), "")
+	l.With("k", "").Error("")
+	assert.Equal(t, `level=error msg="" k=""`+"\n", buf.String())
+}
+
+func TestLogfmtAppender_Keys(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewLogfmtAppender(&buf, 0), "")
+	l.With("user name", 1).With("a=b", 2).With(`"q"`, 3).With("", 4).With("ключ", 5).Notice("keys")
+	assert.Equal(t, `level=notice msg=keys user_name=1 a_b=2 _q_=3 _=4 ключ=5`+"\n", buf.String())
+}
+
+func TestLogfmtAppender_ReservedKeys(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewLogfmtAppender(&buf, 0), "test")
+	l.With("msg", "x").With("level", "fake").With("user", "ok").Notice("real")
+	assert.Equal(t, `level=notice prefix=test msg=real fields.msg=x fields.level=fake user=ok`+"\n", buf.String())
+}
+
+func TestLogfmtAppender_Escape(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewLogfmtAppender(&buf, 0), "")
+	l.With("ctl", "a\x01b").With("esc", "q\"\\\t\r").With("utf", "é\xff").Notice("m")
+	assert.Equal(t, "level=notice msg=m ctl=\"a\x01b\" esc=\"q\\\"\\\\\\t\\r\" utf=\"é\ufffd\"\n", buf.String())
+}
@@ -0,0 +1,93 @@
+/*
+Package logxotel correlates logx entries with OpenTelemetry traces.
+
+Register Fields as context extractor to add "trace_id" and "span_id"
+fields to all entries logged by context-aware methods within active span:
+
+	logx.RegisterContextExtractor(logxotel.Fields)
+
+Register RecordEvents hook to additionally record entries as events of
+active span:
+
+	logx.RegisterContextHook(logxotel.RecordEvents(logx.LevelWarning))
+
+	log.ErrorContext(ctx, "request failed")
+*/
+package logxotel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akaspin/logx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey is key of trace ID field.
+	TraceIDKey = "trace_id"
+
+	// SpanIDKey is key of span ID field.
+	SpanIDKey = "span_id"
+)
+
+// Fields returns trace ID and span ID of span context carried by context.
+// Fields returns nil if context has no valid span context.
+func Fields(ctx context.Context) (fields []logx.Field) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []logx.Field{
+		{Key: TraceIDKey, Value: sc.TraceID().String()},
+		{Key: SpanIDKey, Value: sc.SpanID().String()},
+	}
+}
+
+// RecordEvents returns hook which records entries with given or higher
+// level as events of recording span carried by context. Event name is log
+// line. Level and fields of entry are recorded as event attributes.
+func RecordEvents(level logx.Level) (hook logx.ContextHook) {
+	return func(ctx context.Context, entry *logx.Entry) {
+		if entry.Level < level {
+			return
+		}
+		span := trace.SpanFromContext(ctx)
+		if !span.IsRecording() {
+			return
+		}
+		attrs := make([]attribute.KeyValue, 0, len(entry.Fields)+1)
+		attrs = append(attrs, attribute.String("level", entry.Level.String()))
+		for _, field := range entry.Fields {
+			if field.Key == TraceIDKey || field.Key == SpanIDKey {
+				continue
+			}
+			attrs = append(attrs, attributeOf(field))
+		}
+		span.AddEvent(entry.Line,
+			trace.WithTimestamp(entry.Time),
+			trace.WithAttributes(attrs...))
+	}
+}
+
+func attributeOf(field logx.Field) (attr attribute.KeyValue) {
+	switch v := field.Value.(type) {
+	case string:
+		return attribute.String(field.Key, v)
+	case bool:
+		return attribute.Bool(field.Key, v)
+	case int:
+		return attribute.Int(field.Key, v)
+	case int64:
+		return attribute.Int64(field.Key, v)
+	case float64:
+		return attribute.Float64(field.Key, v)
+	case error:
+		return attribute.String(field.Key, v.Error())
+	case fmt.Stringer:
+		return attribute.String(field.Key, v.String())
+	default:
+		return attribute.String(field.Key, fmt.Sprint(v))
+	}
+}
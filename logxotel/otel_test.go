@@ -0,0 +1,94 @@
+package logxotel_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/akaspin/logx/logxotel"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func init() {
+	logx.RegisterContextExtractor(logxotel.Fields)
+	logx.RegisterContextHook(logxotel.RecordEvents(logx.LevelWarning))
+}
+
+type event struct {
+	name  string
+	attrs []attribute.KeyValue
+}
+
+// recordingSpan records events.
+type recordingSpan struct {
+	noop.Span
+	sc     trace.SpanContext
+	events []event
+}
+
+func (s *recordingSpan) SpanContext() trace.SpanContext {
+	return s.sc
+}
+
+func (s *recordingSpan) IsRecording() bool {
+	return true
+}
+
+func (s *recordingSpan) AddEvent(name string, options ...trace.EventOption) {
+	config := trace.NewEventConfig(options...)
+	s.events = append(s.events, event{
+		name:  name,
+		attrs: config.Attributes(),
+	})
+}
+
+func newSpanContext(t *testing.T) (ctx context.Context, span *recordingSpan) {
+	traceID, err := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	assert.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("0102030405060708")
+	assert.NoError(t, err)
+	span = &recordingSpan{
+		sc: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}),
+	}
+	return trace.ContextWithSpan(context.Background(), span), span
+}
+
+func TestFields(t *testing.T) {
+	ctx, _ := newSpanContext(t)
+	var text, json bytes.Buffer
+	logx.NewLog(logx.NewTextAppender(&text, 0), "test").NoticeContext(ctx, "message")
+	logx.NewLog(logx.NewJSONAppender(&json, 0), "test").NoticeContext(ctx, "message")
+	logx.NewLog(logx.NewTextAppender(&text, 0), "test").NoticeContext(context.Background(), "no span")
+
+	assert.Equal(t, "NOTICE test message trace_id=0102030405060708090a0b0c0d0e0f10 span_id=0102030405060708\n"+
+		"NOTICE test no span\n", text.String())
+	assert.Equal(t, `{"level":"NOTICE","prefix":"test","msg":"message","trace_id":"0102030405060708090a0b0c0d0e0f10","span_id":"0102030405060708"}`+"\n",
+		json.String())
+}
+
+func TestRecordEvents(t *testing.T) {
+	ctx, span := newSpanContext(t)
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, 0), "test")
+	l.NoticeContext(ctx, "skip")
+	l.With("user", "u1").With("attempt", 2).ErrorContext(ctx, "failed")
+
+	assert.Equal(t, []event{
+		{
+			name: "failed",
+			attrs: []attribute.KeyValue{
+				attribute.String("level", "ERROR"),
+				attribute.String("user", "u1"),
+				attribute.Int("attempt", 2),
+			},
+		},
+	}, span.events)
+}
@@ -0,0 +1,180 @@
+/*
+Package logxtest provides appenders for tests.
+
+Recorder captures entries for assertions:
+
+	rec := logxtest.NewRecorder()
+	log := logx.NewLog(rec, "test")
+	log.Warning("disk is full")
+	assert.True(t, rec.Has(logx.LevelWarning, "full"))
+
+NewTBAppender writes entries through testing.TB.Log so they are shown
+only for failed tests or with -v flag. Location reported by testing
+points to logxtest, so use Lshortfile flag to see call site of entry:
+
+	log := logx.NewLog(logxtest.NewTBAppender(t, logx.Lshortfile), "test")
+*/
+package logxtest
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akaspin/logx"
+)
+
+// Entry is captured log entry.
+type Entry struct {
+	Level  logx.Level
+	Prefix string
+	Tags   []string
+	Line   string
+	Fields []logx.Field
+	Time   time.Time
+
+	// Caller is "file:line" of call site with base name of file. Caller is
+	// empty if call site is unknown.
+	Caller string
+}
+
+// Recorder is appender which captures entries. Recorder and all its
+// clones share captured entries. Recorder is safe for concurrent use.
+type Recorder struct {
+	store  *recorderStore
+	prefix string
+	tags   []string
+}
+
+// NewRecorder returns new Recorder without captured entries.
+func NewRecorder() (r *Recorder) {
+	return &Recorder{
+		store: &recorderStore{},
+	}
+}
+
+// Clone returns Recorder with given prefix and tags which shares captured
+// entries with r.
+func (r *Recorder) Clone(prefix string, tags []string) (a logx.Appender) {
+	return &Recorder{
+		store:  r.store,
+		prefix: prefix,
+		tags:   tags,
+	}
+}
+
+// Append captures line with given level. Unknown levels are captured as
+// INFO.
+func (r *Recorder) Append(level, line string) {
+	l, err := logx.ParseLevel(level)
+	if err != nil {
+		l = logx.LevelInfo
+	}
+	r.AppendEntry(&logx.Entry{
+		Level: l,
+		Line:  line,
+		Time:  time.Now(),
+	})
+}
+
+// AppendEntry captures entry.
+func (r *Recorder) AppendEntry(entry *logx.Entry) {
+	captured := Entry{
+		Level:  entry.Level,
+		Prefix: r.prefix,
+		Tags:   r.tags,
+		Line:   strings.TrimSuffix(entry.Line, "\n"),
+		Fields: append([]logx.Field(nil), entry.Fields...),
+		Time:   entry.Time,
+	}
+	if entry.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{entry.PC}).Next()
+		if frame.File != "" {
+			file := frame.File
+			if i := strings.LastIndexByte(file, '/'); i >= 0 {
+				file = file[i+1:]
+			}
+			captured.Caller = file + ":" + strconv.Itoa(frame.Line)
+		}
+	}
+	r.store.mu.Lock()
+	r.store.entries = append(r.store.entries, captured)
+	r.store.mu.Unlock()
+}
+
+// Entries returns copy of captured entries.
+func (r *Recorder) Entries() (entries []Entry) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return append([]Entry(nil), r.store.entries...)
+}
+
+// Has reports whether any entry with given level contains substring.
+func (r *Recorder) Has(level logx.Level, substring string) (ok bool) {
+	return r.Count(level, substring) > 0
+}
+
+// Count returns number of entries with given level which contain
+// substring. Empty substring matches all entries with given level.
+func (r *Recorder) Count(level logx.Level, substring string) (n int) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, entry := range r.store.entries {
+		if entry.Level == level && strings.Contains(entry.Line, substring) {
+			n++
+		}
+	}
+	return n
+}
+
+// Len returns number of captured entries.
+func (r *Recorder) Len() (n int) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return len(r.store.entries)
+}
+
+// Reset discards captured entries.
+func (r *Recorder) Reset() {
+	r.store.mu.Lock()
+	r.store.entries = nil
+	r.store.mu.Unlock()
+}
+
+type recorderStore struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewTBAppender returns TextAppender with given flags which writes each
+// entry through tb.Log. Entries written after test is finished, for
+// example by AsyncAppender, are discarded.
+func NewTBAppender(tb testing.TB, flags int) (a *logx.TextAppender) {
+	w := &tbWriter{tb: tb}
+	tb.Cleanup(w.close)
+	return logx.NewTextAppender(w, flags)
+}
+
+type tbWriter struct {
+	tb     testing.TB
+	mu     sync.Mutex
+	closed bool
+}
+
+func (w *tbWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.closed {
+		w.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+func (w *tbWriter) close() {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+}
@@ -0,0 +1,68 @@
+package logxtest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/akaspin/logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := logxtest.NewRecorder()
+	l := logx.NewLog(rec, "test", "a")
+	l.With("k", 1).Warning("disk is full")
+	l.GetLog("other").Error("failed")
+	l.Error("failed again")
+
+	assert.True(t, rec.Has(logx.LevelWarning, "full"))
+	assert.False(t, rec.Has(logx.LevelError, "full"))
+	assert.Equal(t, 2, rec.Count(logx.LevelError, "failed"))
+	assert.Equal(t, 3, rec.Len())
+
+	entries := rec.Entries()
+	assert.Equal(t, logx.LevelWarning, entries[0].Level)
+	assert.Equal(t, "test", entries[0].Prefix)
+	assert.Equal(t, []string{"a"}, entries[0].Tags)
+	assert.Equal(t, "disk is full", entries[0].Line)
+	assert.Equal(t, []logx.Field{{Key: "k", Value: 1}}, entries[0].Fields)
+	assert.Equal(t, "logxtest_test.go:15", entries[0].Caller)
+	assert.Equal(t, "other", entries[1].Prefix)
+	assert.Nil(t, entries[1].Tags)
+
+	rec.Reset()
+	assert.Equal(t, 0, rec.Len())
+	rec.Append("CRITICAL", "legacy\n")
+	assert.True(t, rec.Has(logx.LevelCritical, "legacy"))
+	assert.Equal(t, "legacy", rec.Entries()[0].Line)
+}
+
+type fakeTB struct {
+	testing.TB
+	lines   []string
+	cleanup []func()
+}
+
+func (tb *fakeTB) Log(args ...interface{}) {
+	tb.lines = append(tb.lines, fmt.Sprint(args...))
+}
+
+func (tb *fakeTB) Cleanup(fn func()) {
+	tb.cleanup = append(tb.cleanup, fn)
+}
+
+func TestNewTBAppender(t *testing.T) {
+	tb := &fakeTB{}
+	l := logx.NewLog(logxtest.NewTBAppender(tb, 0), "test")
+	l.Notice("one")
+	l.Warning("two")
+	for _, fn := range tb.cleanup {
+		fn()
+	}
+	l.Warning("finished")
+	assert.Equal(t, []string{"NOTICE test one", "WARNING test two"}, tb.lines)
+
+	// real testing.T
+	logx.NewLog(logxtest.NewTBAppender(t, logx.Lshortfile), "test").Notice("shown on failure")
+}
@@ -0,0 +1,126 @@
+package logx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// MultiAppender sends each entry to all child appenders. Panic in one
+// child is recovered and reported to stderr and does not affect other
+// children.
+type MultiAppender struct {
+	appenders []Appender
+}
+
+// NewMultiAppender returns appender which sends entries to all given
+// appenders.
+func NewMultiAppender(appenders ...Appender) (a *MultiAppender) {
+	return &MultiAppender{
+		appenders: appenders,
+	}
+}
+
+// Clone returns MultiAppender with clones of all children with given
+// prefix and tags.
+func (a *MultiAppender) Clone(prefix string, tags []string) (a1 Appender) {
+	appenders := make([]Appender, len(a.appenders))
+	for i, child := range a.appenders {
+		appenders[i] = child.Clone(prefix, tags)
+	}
+	return &MultiAppender{
+		appenders: appenders,
+	}
+}
+
+// Append sends line to all children.
+func (a *MultiAppender) Append(level, line string) {
+	l, _ := levelByName(level)
+	entry := &Entry{
+		Level: l,
+		Line:  line,
+		Time:  time.Now(),
+		PC:    callerPC(1),
+	}
+	for _, child := range a.appenders {
+		if _, ok := child.(EntryAppender); ok {
+			safeAppend(child, entry)
+			continue
+		}
+		safeAppendLine(child, level, line)
+	}
+}
+
+// AppendEntry sends entry to all children.
+func (a *MultiAppender) AppendEntry(entry *Entry) {
+	for _, child := range a.appenders {
+		safeAppend(child, entry)
+	}
+}
+
+// TryAppendEntry sends entry to all children and returns first write
+// error of children which implement CheckedAppender.
+func (a *MultiAppender) TryAppendEntry(entry *Entry) (err error) {
+	for _, child := range a.appenders {
+		if cErr := safeTryAppend(child, entry); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// SetErrorHandler sets error handler of all children which implement
+// CheckedAppender.
+func (a *MultiAppender) SetErrorHandler(handler ErrorHandler) {
+	for _, child := range a.appenders {
+		if c, ok := child.(CheckedAppender); ok {
+			c.SetErrorHandler(handler)
+		}
+	}
+}
+
+// WriteFailures returns total number of failed writes of children which
+// implement CheckedAppender.
+func (a *MultiAppender) WriteFailures() (n uint64) {
+	for _, child := range a.appenders {
+		if c, ok := child.(CheckedAppender); ok {
+			n += c.WriteFailures()
+		}
+	}
+	return n
+}
+
+// Flush flushes all children which implement Flusher and returns first
+// error.
+func (a *MultiAppender) Flush(ctx context.Context) (err error) {
+	for _, child := range a.appenders {
+		if f, ok := child.(Flusher); ok {
+			if fErr := f.Flush(ctx); fErr != nil && err == nil {
+				err = fErr
+			}
+		}
+	}
+	return err
+}
+
+func safeAppend(appender Appender, entry *Entry) {
+	defer recoverAppender(appender)
+	appendEntry(appender, entry)
+}
+
+func safeTryAppend(appender Appender, entry *Entry) (err error) {
+	defer recoverAppender(appender)
+	return tryAppendEntry(appender, entry)
+}
+
+func safeAppendLine(appender Appender, level, line string) {
+	defer recoverAppender(appender)
+	appender.Append(level, line)
+}
+
+func recoverAppender(appender Appender) {
+	if r := recover(); r != nil {
+		fmt.Fprintf(os.Stderr, "logx: recovered panic in %T: %v\n", appender, r)
+	}
+}
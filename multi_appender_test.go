@@ -0,0 +1,35 @@
+package logx_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+type panicAppender struct{}
+
+func (panicAppender) Append(level, line string) {
+	panic("bang")
+}
+
+func (a panicAppender) Clone(prefix string, tags []string) logx.Appender {
+	return a
+}
+
+func TestMultiAppender(t *testing.T) {
+	var text, json bytes.Buffer
+	var lines []string
+	l := logx.NewLog(logx.NewMultiAppender(
+		logx.NewTextAppender(&text, logx.Lshortfile),
+		panicAppender{},
+		logx.NewJSONAppender(&json, logx.Lshortfile),
+		lineAppender{lines: &lines},
+	), "test", "a")
+	l.With("k", "v").Notice("message")
+
+	assert.Equal(t, "NOTICE test [a] multi_appender_test.go:30 message k=v\n", text.String())
+	assert.Equal(t, `{"level":"NOTICE","prefix":"test","tags":["a"],"caller":"multi_appender_test.go:30","msg":"message","k":"v"}`+"\n", json.String())
+	assert.Equal(t, []string{"NOTICE message k=v"}, lines)
+}
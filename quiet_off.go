@@ -2,26 +2,57 @@
 
 package logx
 
-import "fmt"
-
-const lInfo = "INFO"
+import (
+	"context"
+	"fmt"
+)
 
 // Print is synonym to Info used for compatibility with "log" package.
 func (l *Log) Print(v ...interface{}) {
-	l.appender.Append(lInfo, fmt.Sprint(v...))
+	if !l.level.enabled(LevelInfo) {
+		return
+	}
+	l.append(LevelInfo, fmt.Sprint(v...))
 }
 
 // Printf is synonym to Infof used for compatibility  "log" package.
 func (l *Log) Printf(format string, v ...interface{}) {
-	l.appender.Append(lInfo, fmt.Sprintf(format, v...))
+	if !l.level.enabled(LevelInfo) {
+		return
+	}
+	l.append(LevelInfo, fmt.Sprintf(format, v...))
 }
 
 // Info logs value with INFO severity level.
 func (l *Log) Info(v ...interface{}) {
-	l.appender.Append(lInfo, fmt.Sprint(v...))
+	if !l.level.enabled(LevelInfo) {
+		return
+	}
+	l.append(LevelInfo, fmt.Sprint(v...))
 }
 
 // Infof logs formatted value with INFO severity level.
 func (l *Log) Infof(format string, v ...interface{}) {
-	l.appender.Append(lInfo, fmt.Sprintf(format, v...))
+	if !l.level.enabled(LevelInfo) {
+		return
+	}
+	l.append(LevelInfo, fmt.Sprintf(format, v...))
+}
+
+// InfoContext logs value with INFO severity level and fields
+// extracted from context.
+func (l *Log) InfoContext(ctx context.Context, v ...interface{}) {
+	if !l.level.enabled(LevelInfo) {
+		return
+	}
+	l.appendContext(ctx, LevelInfo, fmt.Sprint(v...))
+}
+
+// InfofContext logs formatted value with INFO severity level and fields
+// extracted from context.
+func (l *Log) InfofContext(ctx context.Context, format string, v ...interface{}) {
+	if !l.level.enabled(LevelInfo) {
+		return
+	}
+	l.appendContext(ctx, LevelInfo, fmt.Sprintf(format, v...))
 }
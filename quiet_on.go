@@ -2,6 +2,8 @@
 
 package logx
 
+import "context"
+
 // Print is synonym to Info used for compatibility with "log" package.
 func (*Log) Print(v ...interface{}) {}
 
@@ -13,3 +15,11 @@ func (*Log) Info(v ...interface{}) {}
 
 // Infof logs formatted value with INFO severity level.
 func (*Log) Infof(format string, v ...interface{}) {}
+
+// InfoContext logs value with INFO severity level and fields
+// extracted from context.
+func (*Log) InfoContext(ctx context.Context, v ...interface{}) {}
+
+// InfofContext logs formatted value with INFO severity level and fields
+// extracted from context.
+func (*Log) InfofContext(ctx context.Context, format string, v ...interface{}) {}
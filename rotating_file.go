@@ -0,0 +1,299 @@
+package logx
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rotateTimeFormat = "20060102T150405.000000000"
+
+// RotateOptions configures RotatingFile.
+type RotateOptions struct {
+
+	// MaxSize is maximum size of file in bytes. Zero disables rotation
+	// by size.
+	MaxSize int64
+
+	// Interval is maximum age of file. Zero disables rotation by time.
+	Interval time.Duration
+
+	// MaxBackups is maximum number of rotated files to keep. Zero keeps
+	// all rotated files.
+	MaxBackups int
+
+	// Compress enables gzip compression of rotated files.
+	Compress bool
+
+	// Mode is permission bits of created files. Zero means 0644.
+	Mode os.FileMode
+}
+
+/*
+RotatingFile is io.Writer which writes to file and rotates it by size
+and/or time. Rotated files are renamed to "path.TIMESTAMP" and optionally
+compressed to "path.TIMESTAMP.gz". RotatingFile is safe for concurrent use
+and may be used as output of any appender:
+
+	f, err := logx.OpenRotatingFile("/var/log/app.log", logx.RotateOptions{
+		MaxSize:    100 << 20,
+		MaxBackups: 10,
+		Compress:   true,
+	})
+	log := logx.NewLog(logx.NewTextAppender(f, logx.LstdFlags), "app")
+
+Errors of Write are returned to appender. Errors of background
+compression and removal of rotated files are passed to handler set by
+SetErrorHandler and counted by WriteFailures.
+*/
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+	now  func() time.Time
+	*writeErrors
+
+	mu       sync.Mutex
+	file     *os.File
+	closed   bool
+	size     int64
+	openedAt time.Time
+
+	// wg tracks background compression and cleanup
+	wg sync.WaitGroup
+	bg sync.Mutex
+}
+
+// OpenRotatingFile opens or creates file with given path for appending.
+func OpenRotatingFile(path string, opts RotateOptions) (f *RotatingFile, err error) {
+	return openRotatingFile(path, opts, time.Now)
+}
+
+// openRotatingFile opens RotatingFile which uses given clock.
+func openRotatingFile(path string, opts RotateOptions, now func() time.Time) (f *RotatingFile, err error) {
+	if opts.Mode == 0 {
+		opts.Mode = 0644
+	}
+	f = &RotatingFile{
+		path:        path,
+		opts:        opts,
+		now:         now,
+		writeErrors: &writeErrors{},
+		openedAt:    now(),
+	}
+	if err = f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write writes p to file. File is rotated before write if write exceeds
+// maximum size or file is older than rotation interval. If file can't be
+// reopened after rotation, Write fails and next Write tries to open it
+// again.
+func (f *RotatingFile) Write(p []byte) (n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.file != nil && f.needsRotate(int64(len(p))) {
+		if err = f.rotate(); err != nil && f.file == nil {
+			return 0, err
+		}
+	}
+	if f.file == nil {
+		if err = f.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err = f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate forces rotation of file.
+func (f *RotatingFile) Rotate() (err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// Reopen closes and reopens file by path. Use it when file is moved by
+// external tool. Reopen does not reset rotation interval.
+func (f *RotatingFile) Reopen() (err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	err = f.closeFile()
+	if oErr := f.open(); oErr != nil {
+		return oErr
+	}
+	return err
+}
+
+// ReopenOnSignal reopens file on each of given signals, typically
+// syscall.SIGHUP. Returned function stops signal handling.
+func (f *RotatingFile) ReopenOnSignal(sig ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sig...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				f.Reopen()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Close closes file and waits for background compression of rotated
+// files.
+func (f *RotatingFile) Close() (err error) {
+	f.mu.Lock()
+	f.closed = true
+	err = f.closeFile()
+	f.mu.Unlock()
+	f.wg.Wait()
+	return err
+}
+
+// open opens file by path. Rotation interval is not reset.
+func (f *RotatingFile) open() (err error) {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, f.opts.Mode)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// closeFile closes current file. File is detached even if Close fails,
+// so it is reopened by next write.
+func (f *RotatingFile) closeFile() (err error) {
+	if f.file == nil {
+		return nil
+	}
+	err = f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) needsRotate(n int64) (ok bool) {
+	if f.opts.MaxSize > 0 && f.size > 0 && f.size+n > f.opts.MaxSize {
+		return true
+	}
+	// idle empty files are not rotated by time
+	return f.opts.Interval > 0 && f.size > 0 && f.now().Sub(f.openedAt) >= f.opts.Interval
+}
+
+// rotate renames file to backup and opens new file. New file is opened
+// even if old file fails to close. If rename fails, old file is reopened.
+func (f *RotatingFile) rotate() (err error) {
+	closeErr := f.closeFile()
+	backup := f.path + "." + f.now().Format(rotateTimeFormat)
+	if err = os.Rename(f.path, backup); err != nil && !os.IsNotExist(err) {
+		f.open()
+		return err
+	}
+	f.openedAt = f.now()
+	if err = f.open(); err != nil {
+		return err
+	}
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		// serialize background work between rotations
+		f.bg.Lock()
+		defer f.bg.Unlock()
+		if f.opts.Compress {
+			f.report(compressFile(backup, f.opts.Mode))
+		}
+		f.removeBackups()
+	}()
+	return closeErr
+}
+
+// removeBackups removes the oldest rotated files exceeding MaxBackups.
+func (f *RotatingFile) removeBackups() {
+	if f.opts.MaxBackups <= 0 {
+		return
+	}
+	dir, base := filepath.Split(f.path)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		f.report(err)
+		return
+	}
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base+".") {
+			continue
+		}
+		suffix := strings.TrimSuffix(name[len(base)+1:], ".gz")
+		if _, err := time.Parse(rotateTimeFormat, suffix); err == nil {
+			backups = append(backups, filepath.Join(dir, name))
+		}
+	}
+	if len(backups) <= f.opts.MaxBackups {
+		return
+	}
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-f.opts.MaxBackups] {
+		f.report(os.Remove(backup))
+	}
+}
+
+func compressFile(path string, mode os.FileMode) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err == nil {
+		err = zw.Close()
+	}
+	if cErr := dst.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
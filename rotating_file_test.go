@@ -0,0 +1,267 @@
+package logx_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func readBackups(t *testing.T, path string) (res []string) {
+	t.Helper()
+	matches, err := filepath.Glob(path + ".*")
+	assert.NoError(t, err)
+	sort.Strings(matches)
+	for _, match := range matches {
+		f, err := os.Open(match)
+		assert.NoError(t, err)
+		zr, err := gzip.NewReader(f)
+		assert.NoError(t, err)
+		data, err := ioutil.ReadAll(zr)
+		assert.NoError(t, err)
+		f.Close()
+		res = append(res, string(data))
+	}
+	return res
+}
+
+func TestRotatingFile_MaxSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.log")
+
+	f, err := logx.OpenRotatingFile(path, logx.RotateOptions{
+		MaxSize:    20,
+		MaxBackups: 2,
+		Compress:   true,
+	})
+	assert.NoError(t, err)
+	l := logx.NewLog(logx.NewTextAppender(f, 0), "")
+	for _, v := range []string{"1", "2", "3", "4", "5", "6", "7", "8"} {
+		l.Notice(v)
+		time.Sleep(time.Millisecond)
+	}
+	assert.NoError(t, f.Close())
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "NOTICE 7\nNOTICE 8\n", string(data))
+	assert.Equal(t, []string{"NOTICE 3\nNOTICE 4\n", "NOTICE 5\nNOTICE 6\n"}, readBackups(t, path))
+}
+
+// fakeClock is clock advanced by tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() (c *fakeClock) {
+	return &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() (now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestRotatingFile_Interval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.log")
+
+	clock := newFakeClock()
+	f, err := logx.OpenRotatingFileClock(path, logx.RotateOptions{
+		Interval: time.Minute,
+	}, clock.Now)
+	assert.NoError(t, err)
+	f.Write([]byte("1\n"))
+	clock.Advance(time.Second * 59)
+	f.Write([]byte("2\n"))
+	clock.Advance(time.Second)
+	f.Write([]byte("3\n"))
+	assert.NoError(t, f.Close())
+
+	matches, err := filepath.Glob(path + ".*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{path + "." + clock.Now().Format(logx.RotateTimeFormat)}, matches)
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "3\n", string(data))
+}
+
+func TestRotatingFile_IntervalIdle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.log")
+
+	clock := newFakeClock()
+	f, err := logx.OpenRotatingFileClock(path, logx.RotateOptions{
+		Interval: time.Minute,
+	}, clock.Now)
+	assert.NoError(t, err)
+	clock.Advance(time.Hour)
+	f.Write([]byte("1\n"))
+	assert.NoError(t, f.Close())
+
+	matches, err := filepath.Glob(path + ".*")
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestRotatingFile_Concurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.log")
+
+	f, err := logx.OpenRotatingFile(path, logx.RotateOptions{
+		MaxSize: 100,
+	})
+	assert.NoError(t, err)
+	l := logx.NewLog(logx.NewTextAppender(f, 0), "")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Notice("concurrent")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.NoError(t, f.Close())
+
+	matches, err := filepath.Glob(path + "*")
+	assert.NoError(t, err)
+	var total int
+	for _, match := range matches {
+		data, err := ioutil.ReadFile(match)
+		assert.NoError(t, err)
+		assert.True(t, len(data) <= 100, match)
+		total += len(data)
+		for _, line := range strings.SplitAfter(string(data), "\n") {
+			if line != "" {
+				assert.Equal(t, "NOTICE concurrent\n", line)
+			}
+		}
+	}
+	assert.Equal(t, 800*len("NOTICE concurrent\n"), total)
+}
+
+func TestRotatingFile_CompressError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.log")
+
+	clock := newFakeClock()
+	f, err := logx.OpenRotatingFileClock(path, logx.RotateOptions{
+		Compress: true,
+	}, clock.Now)
+	assert.NoError(t, err)
+	var errs []error
+	var mu sync.Mutex
+	f.SetErrorHandler(func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	})
+	// directory in place of compressed backup
+	assert.NoError(t, os.Mkdir(path+"."+clock.Now().Format(logx.RotateTimeFormat)+".gz", 0755))
+	f.Write([]byte("1\n"))
+	assert.NoError(t, f.Rotate())
+	assert.NoError(t, f.Close())
+
+	assert.Equal(t, uint64(1), f.WriteFailures())
+	assert.Len(t, errs, 1)
+}
+
+func TestRotatingFile_MetaPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app[1]*.log")
+
+	f, err := logx.OpenRotatingFile(path, logx.RotateOptions{
+		MaxBackups: 1,
+	})
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		f.Write([]byte("1\n"))
+		assert.NoError(t, f.Rotate())
+		time.Sleep(time.Millisecond)
+	}
+	assert.NoError(t, f.Close())
+
+	infos, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, infos, 2)
+}
+
+func TestRotatingFile_Recover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "logs", "test.log")
+	assert.NoError(t, os.Mkdir(filepath.Dir(path), 0755))
+
+	f, err := logx.OpenRotatingFile(path, logx.RotateOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, os.RemoveAll(filepath.Dir(path)))
+	assert.Error(t, f.Reopen())
+	_, err = f.Write([]byte("lost\n"))
+	assert.Error(t, err)
+
+	assert.NoError(t, os.Mkdir(filepath.Dir(path), 0755))
+	_, err = f.Write([]byte("1\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	_, err = f.Write([]byte("2\n"))
+	assert.Equal(t, os.ErrClosed, err)
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n", string(data))
+}
+
+func TestRotatingFile_ReopenKeepsInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.log")
+
+	clock := newFakeClock()
+	f, err := logx.OpenRotatingFileClock(path, logx.RotateOptions{
+		Interval: time.Minute,
+	}, clock.Now)
+	assert.NoError(t, err)
+	f.Write([]byte("1\n"))
+	clock.Advance(time.Second * 40)
+	assert.NoError(t, f.Reopen())
+	clock.Advance(time.Second * 40)
+	f.Write([]byte("2\n"))
+	assert.NoError(t, f.Close())
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "2\n", string(data))
+}
@@ -0,0 +1,44 @@
+//go:build !windows
+// +build !windows
+
+package logx_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile_ReopenOnSignal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.log")
+
+	f, err := logx.OpenRotatingFile(path, logx.RotateOptions{})
+	assert.NoError(t, err)
+	stop := f.ReopenOnSignal(syscall.SIGHUP)
+	defer stop()
+
+	f.Write([]byte("1\n"))
+	assert.NoError(t, os.Rename(path, path+".moved"))
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	for i := 0; i < 100; i++ {
+		if _, err = os.Stat(path); err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	f.Write([]byte("2\n"))
+	assert.NoError(t, f.Close())
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "2\n", string(data))
+}
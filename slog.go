@@ -0,0 +1,231 @@
+//go:build go1.21
+// +build go1.21
+
+package logx
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	slogLevelTrace    = slog.LevelDebug - 4
+	slogLevelNotice   = slog.LevelInfo + 2
+	slogLevelCritical = slog.LevelError + 4
+
+	slogKeyPrefix = "prefix"
+	slogKeyTags   = "tags"
+	slogKeyStack  = "stack"
+)
+
+// SlogLevel returns slog level which corresponds to level. NOTICE and
+// CRITICAL are mapped to INFO+2 and ERROR+4. TRACE is mapped to DEBUG-4.
+func (l Level) SlogLevel() (res slog.Level) {
+	switch l {
+	case LevelTrace:
+		return slogLevelTrace
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelNotice:
+		return slogLevelNotice
+	case LevelWarning:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	}
+	return slogLevelCritical
+}
+
+// LevelFromSlog returns level which corresponds to slog level. Levels
+// between slog levels are rounded down.
+func LevelFromSlog(level slog.Level) (res Level) {
+	switch {
+	case level < slog.LevelDebug:
+		return LevelTrace
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slogLevelNotice:
+		return LevelInfo
+	case level < slog.LevelWarn:
+		return LevelNotice
+	case level < slog.LevelError:
+		return LevelWarning
+	case level < slogLevelCritical:
+		return LevelError
+	}
+	return LevelCritical
+}
+
+/*
+SlogHandler is slog.Handler which sends records to logx appender.
+Attributes are passed to appender as fields. WithGroup nests group name
+into prefix like Log.Sub does:
+
+	h := logx.NewSlogHandler(appender, nil, "app")
+	slog.New(h).WithGroup("db").Info("query", "rows", 5)
+	// INFO app.db query db.rows=5
+
+Keys of attributes in groups are also qualified by group names separated
+by dots, so attributes added before and after WithGroup remain
+distinguishable.
+*/
+type SlogHandler struct {
+	root     Appender
+	appender Appender
+	prefix   string
+	tags     []string
+	level    slog.Leveler
+	fields   []Field
+	group    string
+}
+
+// NewSlogHandler returns slog handler which sends records to given
+// appender with given prefix and tags. Records below level are discarded.
+// If level is nil all records are sent to appender.
+func NewSlogHandler(appender Appender, level slog.Leveler, prefix string, tags ...string) (h *SlogHandler) {
+	return &SlogHandler{
+		root:     appender,
+		appender: appender.Clone(prefix, tags),
+		prefix:   prefix,
+		tags:     tags,
+		level:    level,
+	}
+}
+
+// Enabled reports whether handler handles records at given level.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) (ok bool) {
+	if h.level == nil {
+		return true
+	}
+	return level >= h.level.Level()
+}
+
+// Handle sends record to appender.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) (err error) {
+	fields := make([]Field, len(h.fields), len(h.fields)+r.NumAttrs())
+	copy(fields, h.fields)
+	r.Attrs(func(attr slog.Attr) bool {
+		fields = appendSlogAttr(fields, h.group, attr)
+		return true
+	})
+	appendEntry(h.appender, &Entry{
+		Level:  LevelFromSlog(r.Level),
+		Line:   r.Message,
+		Fields: fields,
+		Time:   r.Time,
+		PC:     r.PC,
+	})
+	return nil
+}
+
+// WithAttrs returns handler which adds given attributes to all records.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) (res slog.Handler) {
+	if len(attrs) == 0 {
+		return h
+	}
+	h1 := *h
+	h1.fields = make([]Field, len(h.fields), len(h.fields)+len(attrs))
+	copy(h1.fields, h.fields)
+	for _, attr := range attrs {
+		h1.fields = appendSlogAttr(h1.fields, h.group, attr)
+	}
+	return &h1
+}
+
+// WithGroup returns handler with group name nested into prefix which
+// qualifies keys of all following attributes with group name.
+func (h *SlogHandler) WithGroup(name string) (res slog.Handler) {
+	if name == "" {
+		return h
+	}
+	h1 := *h
+	h1.group = h.group + name + "."
+	h1.prefix = name
+	if h.prefix != "" {
+		h1.prefix = h.prefix + DefaultPrefixSeparator + name
+	}
+	h1.appender = h.root.Clone(h1.prefix, h.tags)
+	return &h1
+}
+
+func appendSlogAttr(fields []Field, group string, attr slog.Attr) (res []Field) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			group = group + attr.Key + "."
+		}
+		for _, a := range attr.Value.Group() {
+			fields = appendSlogAttr(fields, group, a)
+		}
+		return fields
+	}
+	return append(fields, Field{
+		Key:   group + attr.Key,
+		Value: attr.Value.Any(),
+	})
+}
+
+// SlogAppender is appender which sends entries to slog.Handler. Prefix
+// and tags are passed to handler as "prefix" and "tags" attributes.
+type SlogAppender struct {
+	root    slog.Handler
+	handler slog.Handler
+}
+
+// NewSlogAppender returns appender which sends entries to given handler.
+func NewSlogAppender(handler slog.Handler) (a *SlogAppender) {
+	return &SlogAppender{
+		root:    handler,
+		handler: handler,
+	}
+}
+
+// Clone returns copy of SlogAppender with given prefix and tags
+func (a *SlogAppender) Clone(prefix string, tags []string) (a1 Appender) {
+	var attrs []slog.Attr
+	if prefix != "" {
+		attrs = append(attrs, slog.String(slogKeyPrefix, prefix))
+	}
+	if len(tags) > 0 {
+		attrs = append(attrs, slog.Any(slogKeyTags, tags))
+	}
+	return &SlogAppender{
+		root:    a.root,
+		handler: a.root.WithAttrs(attrs),
+	}
+}
+
+// Append sends line to handler with given level. Unknown levels are sent
+// as INFO.
+func (a *SlogAppender) Append(level, line string) {
+	l, _ := levelByName(level)
+	a.AppendEntry(&Entry{
+		Level: l,
+		Line:  line,
+		Time:  time.Now(),
+		PC:    callerPC(1),
+	})
+}
+
+// AppendEntry sends entry to handler with fields as attributes.
+func (a *SlogAppender) AppendEntry(entry *Entry) {
+	ctx := context.Background()
+	level := entry.Level.SlogLevel()
+	if !a.handler.Enabled(ctx, level) {
+		return
+	}
+	r := slog.NewRecord(entry.Time, level, entry.Line, entry.PC)
+	for _, field := range entry.Fields {
+		r.AddAttrs(slog.Any(field.Key, field.Value))
+	}
+	if len(entry.Stack) > 0 {
+		r.AddAttrs(slog.Any(slogKeyStack, stackFrames(entry.Stack)))
+	}
+	a.handler.Handle(ctx, r)
+}
@@ -0,0 +1,152 @@
+//go:build go1.22
+// +build go1.22
+
+package logx_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+type entryRecorder struct {
+	mu      *sync.Mutex
+	entries *[]logx.Entry
+}
+
+func (r entryRecorder) Append(level, line string) {}
+
+func (r entryRecorder) Clone(prefix string, tags []string) logx.Appender {
+	return r
+}
+
+func (r entryRecorder) AppendEntry(entry *logx.Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*r.entries = append(*r.entries, *entry)
+}
+
+// unflatten converts dotted keys to nested maps.
+func unflatten(m map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	for k, v := range m {
+		parts := strings.Split(k, ".")
+		current := res
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				current[part] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = v
+	}
+	return res
+}
+
+func TestSlogHandler(t *testing.T) {
+	var entries []logx.Entry
+	h := logx.NewSlogHandler(entryRecorder{mu: &sync.Mutex{}, entries: &entries}, nil, "")
+	slogtest.Run(t, func(t *testing.T) slog.Handler {
+		entries = nil
+		return h
+	}, func(t *testing.T) map[string]any {
+		assert.Len(t, entries, 1)
+		m := map[string]interface{}{
+			slog.LevelKey:   entries[0].Level,
+			slog.MessageKey: entries[0].Line,
+		}
+		if !entries[0].Time.IsZero() {
+			m[slog.TimeKey] = entries[0].Time
+		}
+		for _, field := range entries[0].Fields {
+			m[field.Key] = field.Value
+		}
+		return unflatten(m)
+	})
+}
+
+func TestSlogAppender(t *testing.T) {
+	var buf bytes.Buffer
+	a := logx.NewSlogAppender(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug - 4,
+	}))
+	slogtest.Run(t, func(t *testing.T) slog.Handler {
+		buf.Reset()
+		return logx.NewSlogHandler(a, nil, "")
+	}, func(t *testing.T) map[string]any {
+		var m map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+		return unflatten(m)
+	})
+}
+
+func TestSlogHandler_Group(t *testing.T) {
+	var buf bytes.Buffer
+	h := logx.NewSlogHandler(logx.NewTextAppender(&buf, 0), nil, "app", "a")
+	logger := slog.New(h).With("k", 1)
+	logger.WithGroup("db").Info("query", "rows", 5)
+	logger.WithGroup("db").WithGroup("tx").Warn("commit")
+	slog.New(logx.NewSlogHandler(logx.NewTextAppender(&buf, 0), nil, "")).WithGroup("db").Info("root")
+	assert.Equal(t, "INFO app.db [a] query k=1 db.rows=5\n"+
+		"WARNING app.db.tx [a] commit k=1\n"+
+		"INFO db root\n", buf.String())
+}
+
+func TestSlogHandler_ZeroTime(t *testing.T) {
+	var entries []logx.Entry
+	h := logx.NewSlogHandler(entryRecorder{mu: &sync.Mutex{}, entries: &entries}, nil, "")
+	assert.NoError(t, h.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "message", 0)))
+	assert.Len(t, entries, 1)
+	assert.True(t, entries[0].Time.IsZero())
+
+	var buf bytes.Buffer
+	flags := logx.Ldate | logx.Ltime | logx.Lmicroseconds
+	for _, a := range []logx.Appender{
+		logx.NewTextAppender(&buf, flags),
+		logx.NewJSONAppender(&buf, flags),
+		logx.NewLogfmtAppender(&buf, flags),
+	} {
+		h := logx.NewSlogHandler(a, nil, "")
+		assert.NoError(t, h.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "message", 0)))
+	}
+	assert.Equal(t, "INFO message\n"+
+		`{"level":"INFO","msg":"message"}`+"\n"+
+		"level=info msg=message\n", buf.String())
+}
+
+func TestSlogAppender_Log(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewSlogAppender(slog.NewJSONHandler(&buf, nil)), "test", "a", "b")
+	l.With("k", 1).Notice("message")
+
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	delete(m, slog.TimeKey)
+	assert.Equal(t, map[string]interface{}{
+		"level":  "INFO+2",
+		"msg":    "message",
+		"prefix": "test",
+		"tags":   []interface{}{"a", "b"},
+		"k":      float64(1),
+	}, m)
+}
+
+func TestLevelFromSlog(t *testing.T) {
+	for _, level := range []logx.Level{
+		logx.LevelTrace, logx.LevelDebug, logx.LevelInfo, logx.LevelNotice,
+		logx.LevelWarning, logx.LevelError, logx.LevelCritical,
+	} {
+		assert.Equal(t, level, logx.LevelFromSlog(level.SlogLevel()))
+	}
+}
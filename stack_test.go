@@ -0,0 +1,56 @@
+package logx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func stackHelper(l *logx.Log) {
+	logx.Helper()
+	l.Error("from helper")
+}
+
+func TestLog_WithStack(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, 0), "test").WithStack(logx.LevelError)
+	l.Warning("no stack")
+	stackHelper(l)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Equal(t, "WARNING test no stack", lines[0])
+	assert.Equal(t, "ERROR test from helper", lines[1])
+	assert.Equal(t, "\tgithub.com/akaspin/logx_test.TestLog_WithStack", lines[2])
+	assert.True(t, strings.HasSuffix(lines[3], "/stack_test.go:22"), lines[3])
+	assert.True(t, strings.HasPrefix(lines[3], "\t\t/"), lines[3])
+	assert.Equal(t, "\ttesting.tRunner", lines[4])
+	for _, line := range lines {
+		assert.NotContains(t, line, "akaspin/logx.")
+	}
+}
+
+func TestLog_WithStack_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewJSONAppender(&buf, 0), "test").WithStack(logx.LevelWarning)
+	l.Notice("no stack")
+	l.Warning("stack")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Equal(t, `{"level":"NOTICE","prefix":"test","msg":"no stack"}`, string(lines[0]))
+	var v struct {
+		Stack []string `json:"stack"`
+	}
+	assert.NoError(t, json.Unmarshal(lines[1], &v))
+	assert.True(t, len(v.Stack) > 1)
+	assert.Regexp(t, `^github\.com/akaspin/logx_test\.TestLog_WithStack_JSON /.*/stack_test\.go:40$`, v.Stack[0])
+}
+
+func TestLog_WithStack_Logfmt(t *testing.T) {
+	var buf bytes.Buffer
+	logx.NewLog(logx.NewLogfmtAppender(&buf, 0), "").WithStack(logx.LevelCritical).Critical("stack")
+	assert.Regexp(t, `^level=critical msg=stack stack="github\.com/akaspin/logx_test\.TestLog_WithStack_Logfmt /.*/stack_test\.go:54\\ntesting\.tRunner `, buf.String())
+}
@@ -1,15 +1,157 @@
 package logx
 
 import (
+	"context"
 	"os"
+	"sync/atomic"
 )
 
-// Default TextAppender
+// Default TextAppender. DefaultAppender is appender of initial default
+// log. Reassigning DefaultAppender has no effect, use SetDefaultAppender
+// instead.
 var DefaultAppender = NewTextAppender(os.Stderr, LstdFlags)
 
-var std = NewLog(DefaultAppender, "")
+var std atomic.Value // *Log
+
+func init() {
+	std.Store(NewLog(DefaultAppender, ""))
+}
+
+// Default returns default log.
+func Default() (l *Log) {
+	return std.Load().(*Log)
+}
+
+// SetDefault atomically replaces default log. Logs obtained by GetLog
+// before SetDefault are not affected. Logs obtained by GetFollowingLog
+// write to new default log. Following log can't follow itself, so it is
+// replaced with log which writes to appender of current default log.
+// SetDefault panics if log is nil.
+func SetDefault(l *Log) {
+	if l == nil {
+		panic("logx: SetDefault called with nil log")
+	}
+	if follow, ok := l.appender.(*followAppender); ok {
+		_, appender := follow.current()
+		l = l.clone()
+		l.appender = appender
+	}
+	std.Store(l)
+}
+
+// SetDefaultAppender atomically replaces default log with log which
+// writes to given appender and shares runtime level with current default
+// log. SetDefaultAppender panics if appender is nil.
+func SetDefaultAppender(appender Appender) {
+	if appender == nil {
+		panic("logx: SetDefaultAppender called with nil appender")
+	}
+	for {
+		current := Default()
+		l := current.clone()
+		l.appender = appender.Clone(current.prefix, current.tags)
+		if std.CompareAndSwap(current, l) {
+			return
+		}
+	}
+}
 
 // GetLog returns new independent log instance with given prefix
 func GetLog(prefix string, tags ...string) *Log {
-	return std.GetLog(prefix, tags...)
+	return Default().GetLog(prefix, tags...)
+}
+
+/*
+GetFollowingLog returns log with given prefix and tags which follows
+default log. Each entry is written to appender of current default log and
+filtered by its runtime level. Use GetFollowingLog in package variables
+and init functions to pick up configuration done later in main:
+
+	var log = logx.GetFollowingLog("db")
+
+	func main() {
+		logx.SetDefaultAppender(logx.NewJSONAppender(os.Stdout, logx.LstdFlags))
+		log.Notice("connected") // written as JSON
+	}
+
+Logs derived from following log by GetLog and WithTags follow default
+log too.
+*/
+func GetFollowingLog(prefix string, tags ...string) *Log {
+	return &Log{
+		prefix:     prefix,
+		tags:       tags,
+		appender:   &followAppender{prefix: prefix, tags: tags},
+		level:      newLevelVar(LevelTrace),
+		stackLevel: stackOff,
+		separator:  DefaultPrefixSeparator,
+	}
+}
+
+// SetLevel atomically sets runtime level of default log and all logs
+// obtained by GetLog.
+func SetLevel(level Level) {
+	Default().SetLevel(level)
+}
+
+// followAppender writes entries to clone of appender of current default
+// log.
+type followAppender struct {
+	prefix string
+	tags   []string
+
+	cache atomic.Value // *followCache
+}
+
+type followCache struct {
+	root     *Log
+	appender Appender
+}
+
+// Clone returns followAppender with given prefix and tags.
+func (a *followAppender) Clone(prefix string, tags []string) (a1 Appender) {
+	return &followAppender{prefix: prefix, tags: tags}
+}
+
+// Append writes line with given level if level passes runtime level of
+// default log.
+func (a *followAppender) Append(level, line string) {
+	root, appender := a.current()
+	if l, ok := levelByName(level); ok && !root.Enabled(l) {
+		return
+	}
+	appender.Append(level, line)
+}
+
+// AppendEntry writes entry if its level passes runtime level of default
+// log.
+func (a *followAppender) AppendEntry(entry *Entry) {
+	root, appender := a.current()
+	if !root.Enabled(entry.Level) {
+		return
+	}
+	appendEntry(appender, entry)
+}
+
+// Flush flushes appender of default log if it implements Flusher.
+func (a *followAppender) Flush(ctx context.Context) (err error) {
+	if f, ok := Default().appender.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// current returns default log and clone of its appender. Clone is cached
+// until default log is replaced.
+func (a *followAppender) current() (root *Log, appender Appender) {
+	root = Default()
+	if c, ok := a.cache.Load().(*followCache); ok && c.root == root {
+		return root, c.appender
+	}
+	c := &followCache{
+		root:     root,
+		appender: root.appender.Clone(a.prefix, a.tags),
+	}
+	a.cache.Store(c)
+	return root, c.appender
 }
@@ -0,0 +1,100 @@
+package logx
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"runtime"
+	"strings"
+)
+
+// stdWriterPackages are packages which call io.Writer on behalf of caller.
+// Readers of "bytes" and "strings" write to io.Writer in io.Copy.
+var stdWriterPackages = []string{"log.", "fmt.", "io.", "bufio.", "bytes.", "strings."}
+
+type logWriter struct {
+	log   *Log
+	level Level
+
+	// follow makes writer write to current default log
+	follow bool
+}
+
+// Writer returns io.Writer which logs each written chunk as single entry
+// with given level. Trailing newlines are trimmed. Frames of standard
+// "log", "fmt", "io", "bufio", "bytes" and "strings" packages are skipped
+// when call site is reported, so writes by log.Print, fmt.Fprintf or
+// io.Copy are reported at their caller.
+func (l *Log) Writer(level Level) (w io.Writer) {
+	return &logWriter{
+		log:   l,
+		level: level,
+	}
+}
+
+func (w *logWriter) Write(p []byte) (n int, err error) {
+	l := w.log
+	if w.follow {
+		l = Default()
+	}
+	if !l.level.enabled(w.level) {
+		return len(p), nil
+	}
+	line := string(bytes.TrimRight(p, "\r\n"))
+	l.appendPC(w.level, line, stdCallerPC(1+l.callerSkip))
+	return len(p), nil
+}
+
+// NewStdLogger returns standard *log.Logger which writes entries to given
+// log with given level.
+func NewStdLogger(l *Log, level Level) (res *log.Logger) {
+	return log.New(l.Writer(level), "", 0)
+}
+
+// RedirectStdLog routes output of standard "log" package to default log
+// with given level. Entries are written to current default log, so later
+// SetDefault and SetDefaultAppender calls are followed. Flags and prefix
+// of standard log are cleared because logx adds time, call site and its
+// own prefix. Returned function restores output, flags and prefix of
+// standard log.
+func RedirectStdLog(level Level) (restore func()) {
+	flags := log.Flags()
+	prefix := log.Prefix()
+	output := log.Writer()
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(&logWriter{
+		level:  level,
+		follow: true,
+	})
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(output)
+	}
+}
+
+// stdCallerPC returns program counter of call site skipping given number of
+// frames above function which calls stdCallerPC, helpers and frames of
+// standard packages which write to io.Writer.
+func stdCallerPC(skip int) (pc uintptr) {
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	for i := 0; i < n; i++ {
+		frame, _ := runtime.CallersFrames(pcs[i : i+1]).Next()
+		if isStdWriterFrame(frame.Function) || isHelper(frame.Function) {
+			continue
+		}
+		return pcs[i]
+	}
+	return 0
+}
+
+func isStdWriterFrame(function string) (ok bool) {
+	for _, pkg := range stdWriterPackages {
+		if strings.HasPrefix(function, pkg) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package logx_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_Writer(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, logx.Lshortfile), "test", "a")
+	w := l.Writer(logx.LevelWarning)
+	w.Write([]byte("line\n"))
+	l.SetLevel(logx.LevelError)
+	w.Write([]byte("skip\n"))
+	assert.Equal(t, "WARNING test [a] std_log_test.go:20 line\n", buf.String())
+}
+
+func TestLog_Writer_Fprintf(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, logx.Lshortfile), "test")
+	w := l.Writer(logx.LevelWarning)
+	fmt.Fprintf(w, "formatted %d\n", 1)
+	io.Copy(w, strings.NewReader("copied\n"))
+	bw := bufio.NewWriter(w)
+	bw.WriteString("buffered\n")
+	bw.Flush()
+	assert.Equal(t, ""+
+		"WARNING test std_log_test.go:30 formatted 1\n"+
+		"WARNING test std_log_test.go:31 copied\n"+
+		"WARNING test std_log_test.go:34 buffered\n",
+		buf.String())
+}
+
+func TestNewStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, logx.Lshortfile), "test")
+	std := logx.NewStdLogger(l, logx.LevelNotice)
+	std.Printf("hello %s", "world")
+	std.Println("ln")
+	assert.Equal(t, ""+
+		"NOTICE test std_log_test.go:46 hello world\n"+
+		"NOTICE test std_log_test.go:47 ln\n",
+		buf.String())
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	defer logx.SetDefault(logx.Default())
+	output, flags := log.Writer(), log.Flags()
+	log.SetPrefix("std: ")
+	defer log.SetPrefix("")
+
+	var buf bytes.Buffer
+	restore := logx.RedirectStdLog(logx.LevelWarning)
+	assert.NotEqual(t, output, log.Writer())
+	assert.Equal(t, 0, log.Flags())
+	assert.Equal(t, "", log.Prefix())
+
+	// default log replaced after redirect is followed
+	logx.SetDefault(logx.NewLog(logx.NewTextAppender(&buf, logx.Lshortfile), "test", "a"))
+	log.Print("printed")
+	log.Println("line")
+	logx.Default().SetLevel(logx.LevelError)
+	log.Print("skip")
+	restore()
+
+	assert.Equal(t, output, log.Writer())
+	assert.Equal(t, flags, log.Flags())
+	assert.Equal(t, "std: ", log.Prefix())
+	assert.Equal(t, ""+
+		"WARNING test [a] std_log_test.go:68 printed\n"+
+		"WARNING test [a] std_log_test.go:69 line\n",
+		buf.String())
+}
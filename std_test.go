@@ -0,0 +1,73 @@
+package logx_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetDefault(t *testing.T) {
+	orig := logx.Default()
+	defer logx.SetDefault(orig)
+
+	var old, buf bytes.Buffer
+	logx.SetDefault(logx.NewLog(logx.NewTextAppender(&old, 0), ""))
+	following := logx.GetFollowingLog("follow", "a")
+	fixed := logx.GetLog("fixed")
+
+	logx.SetDefault(logx.NewLog(logx.NewTextAppender(&buf, 0), ""))
+	logx.GetLog("new").Notice("one")
+	following.Notice("two")
+	following.GetLog("child").Notice("three")
+	fixed.Notice("old")
+	assert.Equal(t, "NOTICE new one\nNOTICE follow [a] two\nNOTICE child three\n", buf.String())
+	assert.Equal(t, "NOTICE fixed old\n", old.String())
+
+	buf.Reset()
+	logx.SetLevel(logx.LevelError)
+	following.Warning("skip")
+	following.Error("four")
+	assert.Equal(t, "ERROR follow [a] four\n", buf.String())
+}
+
+func TestSetDefaultAppender(t *testing.T) {
+	orig := logx.Default()
+	defer logx.SetDefault(orig)
+
+	var buf bytes.Buffer
+	logx.SetDefault(logx.NewLog(logx.NewTextAppender(&buf, 0), ""))
+	logx.SetLevel(logx.LevelWarning)
+	following := logx.GetFollowingLog("follow")
+
+	var json bytes.Buffer
+	logx.SetDefaultAppender(logx.NewJSONAppender(&json, 0))
+	following.Notice("skip")
+	following.Warning("one")
+	logx.GetLog("new").Error("two")
+	assert.Equal(t, "", buf.String())
+	assert.Equal(t, `{"level":"WARNING","prefix":"follow","msg":"one"}`+"\n"+
+		`{"level":"ERROR","prefix":"new","msg":"two"}`+"\n", json.String())
+}
+
+func TestSetDefault_Following(t *testing.T) {
+	orig := logx.Default()
+	defer logx.SetDefault(orig)
+
+	var buf bytes.Buffer
+	logx.SetDefault(logx.NewLog(logx.NewTextAppender(&buf, 0), ""))
+	following := logx.GetFollowingLog("follow")
+	logx.SetDefault(following)
+	logx.Default().Notice("one")
+	following.Notice("two")
+	logx.GetLog("child").Notice("three")
+	assert.Equal(t, "NOTICE follow one\nNOTICE follow two\nNOTICE child three\n", buf.String())
+
+	assert.Panics(t, func() {
+		logx.SetDefault(nil)
+	})
+	assert.Panics(t, func() {
+		logx.SetDefaultAppender(nil)
+	})
+}
@@ -0,0 +1,476 @@
+package logx
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyslogFormat is syslog message format.
+type SyslogFormat int
+
+const (
+	// RFC5424 is modern syslog format with structured data.
+	RFC5424 SyslogFormat = iota
+
+	// RFC3164 is legacy BSD syslog format.
+	RFC3164
+)
+
+// SyslogFacility is syslog facility. Zero value means FacilityUser.
+type SyslogFacility int
+
+// Syslog facilities.
+const (
+	FacilityKern SyslogFacility = iota + 1
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthpriv
+	FacilityFtp
+	_
+	_
+	_
+	_
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+const (
+	// syslogSDID is SD-ID of structured data element with tags and fields.
+	// 32473 is private enterprise number reserved for documentation.
+	syslogSDID = "logx@32473"
+
+	syslogNil = "-"
+)
+
+var (
+	syslogLocalAddrs = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+	errNoSyslog     = errors.New("logx: unix syslog socket not found")
+	errSyslogClosed = errors.New("logx: syslog appender is closed")
+)
+
+var syslogSeverities = [...]int{
+	LevelTrace:    7,
+	LevelDebug:    7,
+	LevelInfo:     6,
+	LevelNotice:   5,
+	LevelWarning:  4,
+	LevelError:    3,
+	LevelCritical: 2,
+}
+
+// SyslogOptions configures SyslogAppender.
+type SyslogOptions struct {
+
+	// Network is "unixgram", "unix", "udp" or "tcp". If Network is empty
+	// local syslog socket is used.
+	Network string
+
+	// Address is syslog server address or unix socket path.
+	Address string
+
+	// Format is message format.
+	Format SyslogFormat
+
+	// Facility is syslog facility. Default is FacilityUser.
+	Facility SyslogFacility
+
+	// AppName is APP-NAME in RFC 5424 and TAG in RFC 3164. Default is
+	// program name. AppName is truncated to 48 characters.
+	AppName string
+
+	// Hostname is HOSTNAME of messages. Default is os.Hostname. Hostname
+	// is truncated to 255 characters.
+	Hostname string
+
+	// WriteTimeout limits time of each write, so stalled server does not
+	// block logging goroutines. Default is DefaultSyslogWriteTimeout.
+	WriteTimeout time.Duration
+}
+
+// DefaultSyslogWriteTimeout is default SyslogOptions.WriteTimeout.
+const DefaultSyslogWriteTimeout = time.Second * 5
+
+/*
+SyslogAppender sends entries to syslog server. Log levels are mapped to
+syslog severities:
+
+	CRITICAL   crit
+	ERROR      err
+	WARNING    warning
+	NOTICE     notice
+	INFO       info
+	DEBUG      debug
+	TRACE      debug
+
+In RFC 5424 format log prefix is used as MSGID, tags and fields are sent
+as structured data. In RFC 3164 format prefix, tags and fields are written
+into message like TextAppender does.
+
+Characters of AppName and Hostname which are not printable US-ASCII are
+replaced with underscore.
+
+TCP connections use octet-counted framing. Messages sent to unix stream
+sockets are terminated by newline. Broken connection is reestablished on
+next entry. SyslogAppender and all its clones share the same connection.
+Entries appended after Close are reported as write errors.
+*/
+type SyslogAppender struct {
+	*writeErrors
+	conn   *syslogConn
+	prefix string
+	tags   []string
+
+	identity []byte
+}
+
+// NewSyslogAppender connects to syslog server and returns appender.
+func NewSyslogAppender(opts SyslogOptions) (a *SyslogAppender, err error) {
+	if opts.AppName == "" {
+		opts.AppName = filepath.Base(os.Args[0])
+	}
+	if opts.Hostname == "" {
+		if opts.Hostname, err = os.Hostname(); err != nil {
+			opts.Hostname = syslogNil
+		}
+	}
+	opts.AppName = syslogHeaderField(opts.AppName, 48)
+	opts.Hostname = syslogHeaderField(opts.Hostname, 255)
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = DefaultSyslogWriteTimeout
+	}
+	c := &syslogConn{
+		opts: opts,
+		pid:  strconv.Itoa(os.Getpid()),
+	}
+	if err = c.connect(); err != nil {
+		return nil, err
+	}
+	a = &SyslogAppender{
+		writeErrors: &writeErrors{},
+		conn:        c,
+	}
+	a.setIdentity("", nil)
+	return a, nil
+}
+
+// Clone returns copy of SyslogAppender with given prefix and tags
+func (a *SyslogAppender) Clone(prefix string, tags []string) (a1 Appender) {
+	a2 := &SyslogAppender{
+		writeErrors: a.writeErrors,
+		conn:        a.conn,
+		prefix:      prefix,
+		tags:        tags,
+	}
+	a2.setIdentity(prefix, tags)
+	return a2
+}
+
+// Append sends line with given level. Unknown levels are sent as info.
+func (a *SyslogAppender) Append(level, line string) {
+	l, _ := levelByName(level)
+	a.AppendEntry(&Entry{
+		Level: l,
+		Line:  line,
+		Time:  time.Now(),
+	})
+}
+
+// AppendEntry sends entry to syslog server.
+func (a *SyslogAppender) AppendEntry(entry *Entry) {
+	a.TryAppendEntry(entry)
+}
+
+// TryAppendEntry sends entry to syslog server and returns write error.
+func (a *SyslogAppender) TryAppendEntry(entry *Entry) (err error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	if a.conn.opts.Format == RFC3164 {
+		a.write3164(buf, entry)
+	} else {
+		a.write5424(buf, entry)
+	}
+	err = a.conn.write(buf.Bytes())
+	buf.Reset()
+	bufferPool.Put(buf)
+	return a.report(err)
+}
+
+// Close closes connection to syslog server. Entries appended after Close
+// are not sent.
+func (a *SyslogAppender) Close() (err error) {
+	return a.conn.close()
+}
+
+func (a *SyslogAppender) priority(buf *bytes.Buffer, level Level) {
+	severity := 6
+	if level >= LevelTrace && level <= LevelCritical {
+		severity = syslogSeverities[level]
+	}
+	buf.WriteByte('<')
+	itoaBuf(buf, a.conn.opts.Facility.code()*8+severity, -1)
+	buf.WriteByte('>')
+}
+
+func (a *SyslogAppender) write5424(buf *bytes.Buffer, entry *Entry) {
+	opts := &a.conn.opts
+	a.priority(buf, entry.Level)
+	buf.WriteString("1 ")
+	if entry.Time.IsZero() {
+		buf.WriteString(syslogNil)
+	} else {
+		buf.WriteString(entry.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(opts.Hostname)
+	buf.WriteByte(' ')
+	buf.WriteString(opts.AppName)
+	buf.WriteByte(' ')
+	buf.WriteString(a.conn.pid)
+	buf.WriteByte(' ')
+	if a.prefix == "" {
+		buf.WriteString(syslogNil)
+	} else {
+		writeSyslogName(buf, a.prefix)
+	}
+	buf.WriteByte(' ')
+	if len(a.tags) == 0 && len(entry.Fields) == 0 {
+		buf.WriteString(syslogNil)
+	} else {
+		buf.WriteString("[" + syslogSDID)
+		for _, tag := range a.tags {
+			buf.WriteString(` tag="`)
+			writeSyslogParam(buf, tag)
+			buf.WriteByte('"')
+		}
+		for _, field := range entry.Fields {
+			buf.WriteByte(' ')
+			writeSyslogName(buf, field.Key)
+			buf.WriteString(`="`)
+			writeSyslogParam(buf, rawValue(field.Value))
+			buf.WriteByte('"')
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(trimNewline(entry.Line))
+}
+
+func (a *SyslogAppender) write3164(buf *bytes.Buffer, entry *Entry) {
+	opts := &a.conn.opts
+	a.priority(buf, entry.Level)
+	// RFC 3164 has no value for unknown time
+	ts := entry.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	buf.WriteString(ts.Format(time.Stamp))
+	buf.WriteByte(' ')
+	buf.WriteString(opts.Hostname)
+	buf.WriteByte(' ')
+	buf.WriteString(opts.AppName)
+	buf.WriteByte('[')
+	buf.WriteString(a.conn.pid)
+	buf.WriteString("]:")
+	buf.Write(a.identity)
+	buf.WriteString(trimNewline(entry.Line))
+	writeFields(buf, entry.Fields)
+}
+
+func (a *SyslogAppender) setIdentity(prefix string, tags []string) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.WriteByte(' ')
+	if prefix != "" {
+		buf.WriteString(prefix)
+		buf.WriteByte(' ')
+	}
+	if len(tags) > 0 {
+		buf.WriteByte('[')
+		for i, tag := range tags {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString(tag)
+		}
+		buf.WriteString("] ")
+	}
+	a.identity = make([]byte, buf.Len())
+	copy(a.identity, buf.Bytes())
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// code returns facility code. Zero value is FacilityUser.
+func (f SyslogFacility) code() (res int) {
+	if f == 0 {
+		f = FacilityUser
+	}
+	return int(f) - 1
+}
+
+// syslogHeaderField returns RFC 5424 header field truncated to max
+// characters. Characters which are not printable US-ASCII are replaced
+// with underscore. Empty field is replaced with NILVALUE.
+func syslogHeaderField(field string, max int) (res string) {
+	if len(field) > max {
+		field = field[:max]
+	}
+	if field == "" {
+		return syslogNil
+	}
+	b := []byte(field)
+	for i, c := range b {
+		if c <= ' ' || c >= 0x7f {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// writeSyslogName writes RFC 5424 name. Characters which are not allowed
+// are replaced with underscore. Names are truncated to 32 characters.
+func writeSyslogName(buf *bytes.Buffer, name string) {
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c <= ' ' || c >= 0x7f || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		buf.WriteByte(c)
+	}
+}
+
+// writeSyslogParam writes RFC 5424 PARAM-VALUE with escaped '"', '\' and
+// ']'.
+func writeSyslogParam(buf *bytes.Buffer, value string) {
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '"', '\\', ']':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+}
+
+func trimNewline(line string) (res string) {
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		return line[:len(line)-1]
+	}
+	return line
+}
+
+type syslogConn struct {
+	opts SyslogOptions
+	pid  string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	closed bool
+
+	// stream is set for stream connections. Messages to TCP are framed
+	// by octet counting and messages to unix sockets are terminated by
+	// newline.
+	stream bool
+	octets bool
+}
+
+func (c *syslogConn) connect() (err error) {
+	if network := c.opts.Network; network != "" {
+		c.conn, err = net.Dial(network, c.opts.Address)
+		c.stream = !strings.HasPrefix(network, "udp") && network != "unixgram"
+		c.octets = strings.HasPrefix(network, "tcp")
+		return err
+	}
+	addrs := syslogLocalAddrs
+	if c.opts.Address != "" {
+		addrs = []string{c.opts.Address}
+	}
+	for _, addr := range addrs {
+		for _, network := range []string{"unixgram", "unix"} {
+			if c.conn, err = net.Dial(network, addr); err == nil {
+				c.stream = network == "unix"
+				return nil
+			}
+		}
+	}
+	return errNoSyslog
+}
+
+// write writes message with one reconnect attempt on failure.
+func (c *syslogConn) write(msg []byte) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errSyslogClosed
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.conn == nil {
+			if err = c.connect(); err != nil {
+				continue
+			}
+		}
+		if err = c.writeFrame(msg); err == nil {
+			return nil
+		}
+		c.conn.Close()
+		c.conn = nil
+	}
+	return err
+}
+
+func (c *syslogConn) writeFrame(msg []byte) (err error) {
+	if err = c.conn.SetWriteDeadline(time.Now().Add(c.opts.WriteTimeout)); err != nil {
+		return err
+	}
+	if !c.stream {
+		_, err = c.conn.Write(msg)
+		return err
+	}
+	frame := bufferPool.Get().(*bytes.Buffer)
+	if c.octets {
+		itoaBuf(frame, len(msg), -1)
+		frame.WriteByte(' ')
+		frame.Write(msg)
+	} else {
+		frame.Write(msg)
+		frame.WriteByte('\n')
+	}
+	_, err = frame.WriteTo(c.conn)
+	frame.Reset()
+	bufferPool.Put(frame)
+	return err
+}
+
+func (c *syslogConn) close() (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.conn != nil {
+		err = c.conn.Close()
+		c.conn = nil
+	}
+	return err
+}
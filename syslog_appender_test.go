@@ -0,0 +1,262 @@
+package logx_test
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+var syslogTimestamp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}(Z|[+-]\d{2}:\d{2})`)
+
+func TestSyslogAppender_UDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer pc.Close()
+
+	a, err := logx.NewSyslogAppender(logx.SyslogOptions{
+		Network:  "udp",
+		Address:  pc.LocalAddr().String(),
+		AppName:  "app",
+		Hostname: "host",
+		Facility: logx.FacilityLocal0,
+	})
+	assert.NoError(t, err)
+	defer a.Close()
+
+	l := logx.NewLog(a, "api", "a", "b")
+	l.With("user", `"x"`).Error("failed")
+	l.GetLog("").Notice("plain")
+
+	pid := strconv.Itoa(os.Getpid())
+	buf := make([]byte, 1024)
+	n, _, err := pc.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `<131>1 TS host app `+pid+` api [logx@32473 tag="a" tag="b" user="\"x\""] failed`,
+		syslogTimestamp.ReplaceAllString(string(buf[:n]), "TS"))
+	n, _, err = pc.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `<133>1 TS host app `+pid+` - - plain`,
+		syslogTimestamp.ReplaceAllString(string(buf[:n]), "TS"))
+}
+
+func TestSyslogAppender_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+	frames := make(chan string)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			for {
+				size, err := r.ReadString(' ')
+				if err != nil {
+					break
+				}
+				n, _ := strconv.Atoi(strings.TrimSpace(size))
+				frame := make([]byte, n)
+				if _, err = io.ReadFull(r, frame); err != nil {
+					break
+				}
+				frames <- string(frame)
+			}
+			conn.Close()
+		}
+	}()
+
+	a, err := logx.NewSyslogAppender(logx.SyslogOptions{
+		Network:  "tcp",
+		Address:  ln.Addr().String(),
+		Format:   logx.RFC3164,
+		AppName:  "app",
+		Hostname: "host",
+	})
+	assert.NoError(t, err)
+	defer a.Close()
+
+	l := logx.NewLog(a, "api", "a")
+	l.With("k", 1).Warning("first")
+	assert.Regexp(t, `^<12>\w{3} [ \d]\d \d{2}:\d{2}:\d{2} host app\[\d+\]: api \[a\] first k=1$`, <-frames)
+
+	l.Critical("second")
+	assert.Regexp(t, `^<10>.* second$`, <-frames)
+
+	assert.NoError(t, a.Close())
+	assert.Error(t, a.TryAppendEntry(&logx.Entry{Level: logx.LevelNotice, Line: "closed"}))
+	assert.Equal(t, uint64(1), a.WriteFailures())
+}
+
+func TestSyslogAppender_Reconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+	type frame struct {
+		conn int
+		line string
+	}
+	frames := make(chan frame, 16)
+	go func() {
+		for i := 1; ; i++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(i int, conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					size, err := r.ReadString(' ')
+					if err != nil {
+						return
+					}
+					n, _ := strconv.Atoi(strings.TrimSpace(size))
+					line := make([]byte, n)
+					if _, err = io.ReadFull(r, line); err != nil {
+						return
+					}
+					frames <- frame{conn: i, line: string(line)}
+					if i == 1 {
+						// server drops first connection
+						return
+					}
+				}
+			}(i, conn)
+		}
+	}()
+
+	a, err := logx.NewSyslogAppender(logx.SyslogOptions{
+		Network:  "tcp",
+		Address:  ln.Addr().String(),
+		AppName:  "app",
+		Hostname: "host",
+	})
+	assert.NoError(t, err)
+	defer a.Close()
+
+	l := logx.NewLog(a, "")
+	l.Notice("first")
+	f := <-frames
+	assert.Equal(t, 1, f.conn)
+
+	// entries written before client notices closed connection are lost
+	for i := 0; i < 100 && f.conn == 1; i++ {
+		assert.NoError(t, a.TryAppendEntry(&logx.Entry{Level: logx.LevelNotice, Line: "retry"}))
+		select {
+		case f = <-frames:
+		case <-time.After(time.Millisecond * 20):
+		}
+	}
+	assert.Equal(t, 2, f.conn)
+	assert.True(t, strings.HasSuffix(f.line, " retry"), f.line)
+}
+
+func TestSyslogAppender_WriteTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		// server accepts single connection and never reads
+		conn, err := ln.Accept()
+		ln.Close()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	a, err := logx.NewSyslogAppender(logx.SyslogOptions{
+		Network:      "tcp",
+		Address:      ln.Addr().String(),
+		WriteTimeout: time.Millisecond * 50,
+	})
+	assert.NoError(t, err)
+	defer a.Close()
+	conn := <-accepted
+	defer conn.Close()
+
+	entry := &logx.Entry{Level: logx.LevelNotice, Line: strings.Repeat("x", 1<<20)}
+	start := time.Now()
+	for i := 0; i < 100 && err == nil; i++ {
+		err = a.TryAppendEntry(entry)
+	}
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second*10)
+}
+
+func TestSyslogAppender_Unix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.sock")
+	ln, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+	defer ln.Close()
+	lines := make(chan string)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	a, err := logx.NewSyslogAppender(logx.SyslogOptions{
+		Network:  "unix",
+		Address:  path,
+		AppName:  "my app",
+		Hostname: "host\tname",
+		Facility: logx.FacilityKern,
+	})
+	assert.NoError(t, err)
+	defer a.Close()
+	logx.NewLog(a, "").Notice("one")
+	logx.NewLog(a, "").Error("two")
+
+	assert.Regexp(t, `^<5>1 \S+ host_name my_app [0-9]+ - - one\n$`, <-lines)
+	assert.Regexp(t, `^<3>1 .* two\n$`, <-lines)
+}
+
+func TestSyslogAppender_Unixgram(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logx")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log.sock")
+	pc, err := net.ListenPacket("unixgram", path)
+	assert.NoError(t, err)
+	defer pc.Close()
+
+	a, err := logx.NewSyslogAppender(logx.SyslogOptions{
+		Address:  path,
+		AppName:  "app",
+		Hostname: "host",
+	})
+	assert.NoError(t, err)
+	defer a.Close()
+	logx.NewLog(a, "").Notice("notice")
+
+	buf := make([]byte, 1024)
+	n, _, err := pc.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^<13>1 .* host app [0-9]+ - - notice$`, string(buf[:n]))
+}
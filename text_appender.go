@@ -3,7 +3,7 @@ package logx
 import (
 	"bytes"
 	"io"
-	"runtime"
+	"os"
 	"sync"
 	"time"
 	"unicode"
@@ -32,10 +32,24 @@ const (
 	// Lcompact removes whitespace from log lines
 	Lcompact
 
+	// Lcolor colors level of TextAppender by severity if output is
+	// terminal. See NewTextAppender.
+	Lcolor
+
+	// LcolorPrefix colors prefix and tags of TextAppender like level.
+	// Assumes Lcolor.
+	LcolorPrefix
+
+	// Lsync serializes writes of appender and all its clones to output.
+	// Use it for outputs which are not safe for concurrent use like
+	// bytes.Buffer, bufio.Writer or network connections. Appenders
+	// created separately with the same output do not share lock and
+	// their writes may still interleave. Create one appender and clone
+	// it, or pass output guarded by own mutex to all appenders.
+	Lsync
+
 	// LstdFlags initial values for the standard logger
 	LstdFlags = Lshortfile | Lcompact
-
-	lCallLevel = 2
 )
 
 var bufferPool = sync.Pool{
@@ -44,26 +58,56 @@ var bufferPool = sync.Pool{
 	},
 }
 
+const colorReset = "\x1b[0m"
+
+var levelColors = [...]string{
+	LevelTrace:    "\x1b[90m",
+	LevelDebug:    "\x1b[90m",
+	LevelInfo:     "\x1b[32m",
+	LevelNotice:   "\x1b[36m",
+	LevelWarning:  "\x1b[33m",
+	LevelError:    "\x1b[31m",
+	LevelCritical: "\x1b[1;31m",
+}
+
 /*
 TextAppender is default for logx
 
 Format:
 
 	time LEVEL prefix [tags] file:line message
+
+Captured stack is written as indented continuation lines:
+
+	ERROR prefix main.go:10 message
+		main.handle
+			/src/main.go:10
+		main.main
+			/src/main.go:4
 */
 type TextAppender struct {
 	output io.Writer
 	flags  int
+	color  bool
+	*writeErrors
 
 	identity []byte
 }
 
-// NewTextAppender returns new appender without prefix and tags
+// NewTextAppender returns new appender without prefix and tags.
+//
+// With Lcolor flag level is colored with ANSI escape sequences if output
+// is terminal. FORCE_COLOR environment variable enables colors for any
+// output. Non-empty NO_COLOR environment variable disables colors
+// regardless of flags and FORCE_COLOR. Without colors output is the same
+// as without Lcolor flag.
 func NewTextAppender(output io.Writer, flags int) (a *TextAppender) {
 	a = &TextAppender{
-		output: output,
-		flags:  flags,
-		identity: []byte(" "),
+		output:      syncOutput(output, flags),
+		flags:       flags,
+		writeErrors: &writeErrors{},
+		color:       flags&Lcolor != 0 && colorEnabled(output),
+		identity:    []byte(" "),
 	}
 	return a
 }
@@ -71,89 +115,97 @@ func NewTextAppender(output io.Writer, flags int) (a *TextAppender) {
 // Clone returns copy of TextAppender with given prefix and tags
 func (a *TextAppender) Clone(prefix string, tags []string) (a1 Appender) {
 	a1 = &TextAppender{
-		output: a.output,
-		flags:  a.flags,
+		output:      a.output,
+		flags:       a.flags,
+		writeErrors: a.writeErrors,
+		color:       a.color,
 	}
 	a1.(*TextAppender).setIdentity(prefix, tags)
 	return a1
 }
 
+// Append writes log line with given level.
 func (a *TextAppender) Append(level, line string) {
+	a.write(level, &Entry{
+		Line: line,
+		Time: time.Now(),
+		PC:   callerPC(1),
+	})
+}
+
+// AppendEntry writes log entry with fields rendered after message as
+// "key=value" pairs.
+func (a *TextAppender) AppendEntry(entry *Entry) {
+	a.write(entry.Level.String(), entry)
+}
+
+// TryAppendEntry writes log entry and returns write error.
+func (a *TextAppender) TryAppendEntry(entry *Entry) (err error) {
+	return a.write(entry.Level.String(), entry)
+}
+
+func (a *TextAppender) write(level string, entry *Entry) (err error) {
+	line := entry.Line
 	buf := bufferPool.Get().(*bytes.Buffer)
 
 	// time
-	if a.flags&(Ldate|Ltime|Lmicroseconds|LUTC) != 0 {
-		t := time.Now()
-		if a.flags&LUTC != 0 {
-			t = t.UTC()
-		}
-		if a.flags&(Ldate|Ltime|Lmicroseconds) != 0 {
-			if a.flags&Ldate != 0 {
-				year, month, day := t.Date()
-				itoaBuf(buf, year, 4)
-				buf.WriteByte('/')
-				itoaBuf(buf, int(month), 2)
-				buf.WriteByte('/')
-				itoaBuf(buf, day, 2)
-				buf.WriteByte(' ')
-			}
-			if a.flags&(Ltime|Lmicroseconds) != 0 {
-				hour, min, sec := t.Clock()
-				itoaBuf(buf, hour, 2)
-				buf.WriteByte(':')
-				itoaBuf(buf, min, 2)
-				buf.WriteByte(':')
-				itoaBuf(buf, sec, 2)
-				if a.flags&Lmicroseconds != 0 {
-					buf.WriteByte('.')
-					itoaBuf(buf, t.Nanosecond()/1e3, 6)
-				}
-				buf.WriteByte(' ')
-			}
-		}
+	if a.flags&(Ldate|Ltime|Lmicroseconds) != 0 && !entry.Time.IsZero() {
+		writeTime(buf, entry.Time, a.flags)
+		buf.WriteByte(' ')
 	}
 
-	// level
-	buf.WriteString(level)
-
-	// identity
-	buf.Write(a.identity)
+	// level and identity
+	if a.color {
+		a.writeColored(buf, level)
+	} else {
+		buf.WriteString(level)
+		buf.Write(a.identity)
+	}
 
 	// file
 	if a.flags&(Lshortfile|Llongfile) != 0 {
-		_, file, lineNo, ok := runtime.Caller(lCallLevel)
-		if !ok {
-			file = "???"
-			lineNo = 0
-		}
-		if a.flags&Lshortfile != 0 {
-			short := file
-			for i := len(file) - 1; i > 0; i-- {
-				if file[i] == '/' {
-					short = file[i+1:]
-					break
-				}
-			}
-			file = short
-		}
-		buf.WriteString(file)
-		buf.WriteByte(':')
-		itoaBuf(buf, lineNo, -1)
+		writeCaller(buf, a.flags, entry.PC)
 		buf.WriteByte(' ')
 	}
 
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		line = line[:len(line)-1]
+	}
 	if a.flags&(Lcompact) != 0 {
 		stripBuf(buf, line)
 	} else {
 		buf.WriteString(line)
 	}
-
-	if len(line) == 0 || line[len(line)-1] != '\n' {
-		buf.WriteByte('\n')
-	}
-	buf.WriteTo(a.output)
+	writeFields(buf, entry.Fields)
+	writeStack(buf, entry.Stack)
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(a.output)
 	buf.Reset()
 	bufferPool.Put(buf)
+	return a.report(err)
+}
+
+// writeColored writes level and identity colored by severity.
+func (a *TextAppender) writeColored(buf *bytes.Buffer, level string) {
+	l, ok := levelByName(level)
+	if !ok {
+		buf.WriteString(level)
+		buf.Write(a.identity)
+		return
+	}
+	color := levelColors[l]
+	buf.WriteString(color)
+	buf.WriteString(level)
+	buf.WriteString(colorReset)
+	if a.flags&LcolorPrefix == 0 || len(a.identity) < 2 {
+		buf.Write(a.identity)
+		return
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(color)
+	buf.Write(a.identity[1 : len(a.identity)-1])
+	buf.WriteString(colorReset)
+	buf.WriteByte(' ')
 }
 
 func (a *TextAppender) setIdentity(prefix string, tags []string) {
@@ -180,6 +232,80 @@ func (a *TextAppender) setIdentity(prefix string, tags []string) {
 	bufferPool.Put(buf)
 }
 
+// syncWriter serializes writes to wrapped writer.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	n, err = w.w.Write(p)
+	w.mu.Unlock()
+	return n, err
+}
+
+// syncOutput wraps output with syncWriter if Lsync flag is set. Clones of
+// appender share the same syncWriter. Appenders created separately get
+// their own syncWriter even for the same output.
+func syncOutput(output io.Writer, flags int) (res io.Writer) {
+	if flags&Lsync == 0 {
+		return output
+	}
+	if _, ok := output.(*syncWriter); ok {
+		return output
+	}
+	return &syncWriter{w: output}
+}
+
+// colorEnabled reports whether colors should be written to output.
+func colorEnabled(output io.Writer) (ok bool) {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	switch os.Getenv("FORCE_COLOR") {
+	case "", "0", "false":
+	default:
+		return true
+	}
+	f, isFile := output.(*os.File)
+	if !isFile {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// writeTime writes time formatted according to flags.
+func writeTime(buf *bytes.Buffer, t time.Time, flags int) {
+	if flags&LUTC != 0 {
+		t = t.UTC()
+	}
+	if flags&Ldate != 0 {
+		year, month, day := t.Date()
+		itoaBuf(buf, year, 4)
+		buf.WriteByte('/')
+		itoaBuf(buf, int(month), 2)
+		buf.WriteByte('/')
+		itoaBuf(buf, day, 2)
+		if flags&(Ltime|Lmicroseconds) != 0 {
+			buf.WriteByte(' ')
+		}
+	}
+	if flags&(Ltime|Lmicroseconds) != 0 {
+		hour, min, sec := t.Clock()
+		itoaBuf(buf, hour, 2)
+		buf.WriteByte(':')
+		itoaBuf(buf, min, 2)
+		buf.WriteByte(':')
+		itoaBuf(buf, sec, 2)
+		if flags&Lmicroseconds != 0 {
+			buf.WriteByte('.')
+			itoaBuf(buf, t.Nanosecond()/1e3, 6)
+		}
+	}
+}
+
 func itoaBuf(buf *bytes.Buffer, i int, wid int) {
 	var b [20]byte
 	bp := len(b) - 1
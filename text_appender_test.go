@@ -0,0 +1,151 @@
+package logx_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/akaspin/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func setColorEnv(noColor, forceColor string) (restore func()) {
+	oldNo, hasNo := os.LookupEnv("NO_COLOR")
+	oldForce, hasForce := os.LookupEnv("FORCE_COLOR")
+	os.Setenv("NO_COLOR", noColor)
+	os.Setenv("FORCE_COLOR", forceColor)
+	return func() {
+		if hasNo {
+			os.Setenv("NO_COLOR", oldNo)
+		} else {
+			os.Unsetenv("NO_COLOR")
+		}
+		if hasForce {
+			os.Setenv("FORCE_COLOR", oldForce)
+		} else {
+			os.Unsetenv("FORCE_COLOR")
+		}
+	}
+}
+
+func TestTextAppender_Color(t *testing.T) {
+	defer setColorEnv("", "1")()
+
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, logx.Lcolor), "test", "a")
+	l.Warning("one")
+	l.Error("two")
+	logx.NewLog(logx.NewTextAppender(&buf, logx.Lcolor|logx.LcolorPrefix), "test", "a").Critical("three")
+	logx.NewLog(logx.NewTextAppender(&buf, logx.Lcolor|logx.LcolorPrefix), "").Notice("four")
+	assert.Equal(t, "\x1b[33mWARNING\x1b[0m test [a] one\n"+
+		"\x1b[31mERROR\x1b[0m test [a] two\n"+
+		"\x1b[1;31mCRITICAL\x1b[0m \x1b[1;31mtest [a]\x1b[0m three\n"+
+		"\x1b[36mNOTICE\x1b[0m four\n", buf.String())
+}
+
+func TestTextAppender_NoColor(t *testing.T) {
+	var buf, plain bytes.Buffer
+
+	restore := setColorEnv("1", "1")
+	logx.NewLog(logx.NewTextAppender(&buf, logx.Lcolor|logx.LcolorPrefix), "test", "a").Warning("one")
+	restore()
+
+	// not a terminal
+	restore = setColorEnv("", "")
+	logx.NewLog(logx.NewTextAppender(&buf, logx.Lcolor), "test", "a").Warning("one")
+	restore()
+
+	logx.NewLog(logx.NewTextAppender(&plain, 0), "test", "a").Warning("one")
+	assert.Equal(t, plain.String()+plain.String(), buf.String())
+}
+
+var errBroken = errors.New("broken")
+
+// brokenWriter fails writes while broken is set.
+type brokenWriter struct {
+	bytes.Buffer
+	broken bool
+}
+
+func (w *brokenWriter) Write(p []byte) (n int, err error) {
+	if w.broken {
+		return 0, errBroken
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestTextAppender_ErrorHandler(t *testing.T) {
+	w := &brokenWriter{broken: true}
+	a := logx.NewTextAppender(w, 0)
+	var errs []error
+	a.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+	l := logx.NewLog(a, "test")
+	l.Notice("one")
+	l.GetLog("child").Notice("two")
+	w.broken = false
+	l.Notice("three")
+
+	assert.Equal(t, []error{errBroken, errBroken}, errs)
+	assert.Equal(t, uint64(2), a.WriteFailures())
+	assert.Equal(t, "NOTICE test three\n", w.String())
+	assert.NoError(t, a.TryAppendEntry(&logx.Entry{Level: logx.LevelNotice, Line: "four"}))
+}
+
+func TestTextAppender_Lsync(t *testing.T) {
+	var buf bytes.Buffer
+	l := logx.NewLog(logx.NewTextAppender(&buf, logx.Lsync), "test")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(child *logx.Log) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				child.Notice(strings.Repeat("x", 100))
+			}
+		}(l.GetLog("child"))
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 800)
+	for _, line := range lines {
+		assert.Equal(t, "NOTICE child "+strings.Repeat("x", 100), line)
+	}
+}
+
+func BenchmarkTextAppender(b *testing.B) {
+	l := logx.NewLog(logx.NewTextAppender(ioutil.Discard, logx.LstdFlags), "test", "a")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.With("n", i).Notice("benchmark message")
+	}
+}
+
+func BenchmarkTextAppender_Parallel(b *testing.B) {
+	l := logx.NewLog(logx.NewTextAppender(ioutil.Discard, logx.LstdFlags), "test", "a")
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Notice("benchmark message")
+		}
+	})
+}
+
+func BenchmarkTextAppender_Parallel_Lsync(b *testing.B) {
+	l := logx.NewLog(logx.NewTextAppender(ioutil.Discard, logx.LstdFlags|logx.Lsync), "test", "a")
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Notice("benchmark message")
+		}
+	})
+}
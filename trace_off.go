@@ -2,6 +2,8 @@
 
 package logx
 
+import "context"
+
 // Trace logs value with TRACE severity level only
 // if "trace" tag is provided on build.
 func (*Log) Trace(v ...interface{}) {}
@@ -9,3 +11,11 @@ func (*Log) Trace(v ...interface{}) {}
 // Tracef logs formatted value with TRACE severity level only
 // if "trace" tag is provided on build.
 func (*Log) Tracef(format string, v ...interface{}) {}
+
+// TraceContext logs value with TRACE severity level and fields
+// extracted from context only if "trace" tag is provided on build.
+func (*Log) TraceContext(ctx context.Context, v ...interface{}) {}
+
+// TracefContext logs formatted value with TRACE severity level and fields
+// extracted from context only if "trace" tag is provided on build.
+func (*Log) TracefContext(ctx context.Context, format string, v ...interface{}) {}
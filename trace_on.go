@@ -3,21 +3,42 @@
 package logx
 
 import (
+	"context"
 	"fmt"
 )
 
-const (
-	lTrace = "TRACE"
-)
-
 // Trace logs value with TRACE severity level only
 // if "trace" tag is provided on build.
 func (l *Log) Trace(v ...interface{}) {
-	l.appender.Append(lTrace, fmt.Sprint(v...))
+	if !l.level.enabled(LevelTrace) {
+		return
+	}
+	l.append(LevelTrace, fmt.Sprint(v...))
 }
 
 // Tracef logs formatted value with TRACE severity level only
 // if "trace" tag is provided on build.
 func (l *Log) Tracef(format string, v ...interface{}) {
-	l.appender.Append(lTrace, fmt.Sprintf(format, v...))
+	if !l.level.enabled(LevelTrace) {
+		return
+	}
+	l.append(LevelTrace, fmt.Sprintf(format, v...))
+}
+
+// TraceContext logs value with TRACE severity level and fields
+// extracted from context only if "trace" tag is provided on build.
+func (l *Log) TraceContext(ctx context.Context, v ...interface{}) {
+	if !l.level.enabled(LevelTrace) {
+		return
+	}
+	l.appendContext(ctx, LevelTrace, fmt.Sprint(v...))
+}
+
+// TracefContext logs formatted value with TRACE severity level and fields
+// extracted from context only if "trace" tag is provided on build.
+func (l *Log) TracefContext(ctx context.Context, format string, v ...interface{}) {
+	if !l.level.enabled(LevelTrace) {
+		return
+	}
+	l.appendContext(ctx, LevelTrace, fmt.Sprintf(format, v...))
 }